@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler exposes patron account balances, payments and waivers
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler instance
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+// Register adds the patron account routes to the router group
+func (h *AccountHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	accounts := rg.Group("/users/:id/account")
+	accounts.GET("", auth.RequireSelfOr("id", models.PermAccountsRead), h.GetBalance)
+	accounts.GET("/entries", auth.RequireSelfOr("id", models.PermAccountsRead), h.ListEntries)
+	accounts.POST("/payments", auth.Require(models.PermPaymentsRecord), h.RecordPayment)
+	accounts.POST("/waivers", auth.Require(models.PermFinesWaive), h.WaiveFine)
+
+	rg.GET("/receipts/:number", auth.Require(models.PermAccountsRead), h.GetReceipt)
+}
+
+// paymentRequest is the body for recording a payment
+type paymentRequest struct {
+	Amount        float64 `json:"amount" binding:"required"`
+	PaymentMethod string  `json:"payment_method"`
+	BorrowingID   *int64  `json:"borrowing_id"`
+}
+
+// waiverRequest is the body for waiving a fine
+type waiverRequest struct {
+	Amount      float64 `json:"amount" binding:"required"`
+	Reason      string  `json:"reason"`
+	BorrowingID *int64  `json:"borrowing_id"`
+}
+
+// GetBalance returns a patron's account summary
+func (h *AccountHandler) GetBalance(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	balance, err := h.accountService.GetBalance(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": balance})
+}
+
+// ListEntries returns a page of a patron's ledger
+func (h *AccountHandler) ListEntries(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	page, pageSize := paginationParams(c)
+	entries, total, err := h.accountService.ListEntries(userID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      entries,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// RecordPayment takes a payment and returns its receipt
+func (h *AccountHandler) RecordPayment(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req paymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	receipt, err := h.accountService.RecordPayment(
+		userID, req.Amount, req.PaymentMethod, req.BorrowingID, currentActor(c),
+	)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": receipt})
+}
+
+// WaiveFine forgives some or all of a patron's fines
+func (h *AccountHandler) WaiveFine(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req waiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	entry, err := h.accountService.WaiveFine(
+		userID, req.Amount, req.Reason, req.BorrowingID, currentActor(c),
+	)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": entry})
+}
+
+// GetReceipt returns a previously issued receipt
+func (h *AccountHandler) GetReceipt(c *gin.Context) {
+	receipt, err := h.accountService.GetReceipt(c.Param("number"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": receipt})
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthLogHandler exposes the security audit log to admins
+type AuthLogHandler struct {
+	authLogService *service.AuthLogService
+}
+
+// NewAuthLogHandler creates a new AuthLogHandler instance
+func NewAuthLogHandler(authLogService *service.AuthLogService) *AuthLogHandler {
+	return &AuthLogHandler{authLogService: authLogService}
+}
+
+// Register adds the auth log routes to the router group
+func (h *AuthLogHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	logs := rg.Group("/admin/auth-logs", auth.Require(models.PermAuditLogsRead))
+	logs.GET("", h.Search)
+	logs.GET("/export", h.Export)
+	logs.GET("/anomalies", h.Anomalies)
+}
+
+// Search returns a page of auth logs filtered by the user_id, action,
+// status, ip, from and to query parameters
+func (h *AuthLogHandler) Search(c *gin.Context) {
+	filters, ok := authLogFilters(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := paginationParams(c)
+	logs, total, err := h.authLogService.Search(currentUserID(c), filters, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      logs,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Export downloads the matching auth logs as CSV or NDJSON, chosen with the
+// format query parameter
+func (h *AuthLogHandler) Export(c *gin.Context) {
+	filters, ok := authLogFilters(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", models.AuthLogExportCSV)
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case models.AuthLogExportCSV:
+	case models.AuthLogExportNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		respondError(c, models.ErrInvalidExportFormat)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=auth-logs."+format)
+
+	err := h.authLogService.Export(currentUserID(c), filters, format, c.Writer)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			respondError(c, err)
+			return
+		}
+		// The status is already sent, so the download just ends early
+		c.Error(err)
+	}
+}
+
+// Anomalies returns suspicious login patterns since the optional since
+// query parameter
+func (h *AuthLogHandler) Anomalies(c *gin.Context) {
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, ok := parseTimeQuery(value, false)
+		if !ok {
+			respondError(c, models.ErrInvalidAuthLogFilter)
+			return
+		}
+		since = parsed
+	}
+
+	anomalies, err := h.authLogService.Anomalies(currentUserID(c), since)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": anomalies})
+}
+
+// authLogFilters reads the auth log filters from the query string and
+// responds with 400 when one is malformed
+func authLogFilters(c *gin.Context) (models.AuthLogFilters, bool) {
+	filters := models.AuthLogFilters{
+		Action:    c.Query("action"),
+		Status:    c.Query("status"),
+		IPAddress: c.Query("ip"),
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || userID < 1 {
+			respondError(c, models.ErrInvalidAuthLogFilter)
+			return filters, false
+		}
+		filters.UserID = userID
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, ok := parseTimeQuery(value, false)
+		if !ok {
+			respondError(c, models.ErrInvalidAuthLogFilter)
+			return filters, false
+		}
+		filters.From = from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, ok := parseTimeQuery(value, true)
+		if !ok {
+			respondError(c, models.ErrInvalidAuthLogFilter)
+			return filters, false
+		}
+		filters.To = to
+	}
+
+	return filters, true
+}
+
+// parseTimeQuery reads an RFC 3339 timestamp or a calendar date. A date used
+// as the end of a range covers the whole day.
+func parseTimeQuery(value string, endOfRange bool) (time.Time, bool) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, true
+	}
+
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfRange {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, true
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookCopyHandler exposes item-level inventory endpoints
+type BookCopyHandler struct {
+	bookCopyService *service.BookCopyService
+}
+
+// NewBookCopyHandler creates a new BookCopyHandler instance
+func NewBookCopyHandler(bookCopyService *service.BookCopyService) *BookCopyHandler {
+	return &BookCopyHandler{bookCopyService: bookCopyService}
+}
+
+// Register adds the inventory routes to the router group
+func (h *BookCopyHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	rg.GET("/books/:id/copies", auth.Authenticated(), h.List)
+	rg.POST("/books/:id/copies", auth.Require(models.PermBooksWrite), h.Add)
+
+	copies := rg.Group("/book-copies", auth.Require(models.PermBooksWrite))
+	copies.PUT("/:id/location", h.Move)
+	copies.POST("/:id/retire", h.Retire)
+}
+
+// addCopyRequest is the body for adding a copy of a book
+type addCopyRequest struct {
+	Barcode         string `json:"barcode" binding:"required"`
+	CopyNumber      string `json:"copy_number"`
+	Location        string `json:"location"`
+	Condition       string `json:"condition"`
+	AcquisitionDate string `json:"acquisition_date"`
+	Notes           string `json:"notes"`
+}
+
+// moveCopyRequest is the body for moving a copy to another shelf
+type moveCopyRequest struct {
+	Location string `json:"location" binding:"required"`
+}
+
+// retireCopyRequest is the body for taking a copy out of the collection
+type retireCopyRequest struct {
+	Status string `json:"status" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+// List returns every copy of a book, including retired ones
+func (h *BookCopyHandler) List(c *gin.Context) {
+	bookID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	copies, err := h.bookCopyService.ListCopies(bookID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copies})
+}
+
+// Add registers a new physical copy of a book
+func (h *BookCopyHandler) Add(c *gin.Context) {
+	bookID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req addCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	bookCopy := &models.BookCopy{
+		Barcode:    req.Barcode,
+		CopyNumber: req.CopyNumber,
+		Location:   req.Location,
+		Condition:  req.Condition,
+		Notes:      req.Notes,
+	}
+	if req.AcquisitionDate != "" {
+		acquired, err := time.Parse(dateLayout, req.AcquisitionDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": "acquisition_date must be YYYY-MM-DD"})
+			return
+		}
+		bookCopy.AcquisitionDate = &acquired
+	}
+
+	if err := h.bookCopyService.AddCopy(bookID, bookCopy, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": bookCopy})
+}
+
+// Move changes the shelving location of a copy
+func (h *BookCopyHandler) Move(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req moveCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	if err := h.bookCopyService.MoveCopy(id, req.Location, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Retire marks a copy as lost, damaged or withdrawn
+func (h *BookCopyHandler) Retire(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req retireCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	if err := h.bookCopyService.RetireCopy(id, req.Status, req.Notes, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
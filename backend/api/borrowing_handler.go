@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BorrowingHandler exposes circulation endpoints
+type BorrowingHandler struct {
+	borrowingService *service.BorrowingService
+}
+
+// NewBorrowingHandler creates a new BorrowingHandler instance
+func NewBorrowingHandler(borrowingService *service.BorrowingService) *BorrowingHandler {
+	return &BorrowingHandler{borrowingService: borrowingService}
+}
+
+// Register adds the circulation routes to the router group
+func (h *BorrowingHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	borrowings := rg.Group("/borrowings", auth.Authenticated())
+	borrowings.GET("/:id/fine-assessments", h.ListFineAssessments)
+	borrowings.POST("/:id/renew", h.Renew)
+	borrowings.GET("/:id/renewals", h.ListRenewals)
+}
+
+// ListFineAssessments returns the fine calculations made for a borrowing
+func (h *BorrowingHandler) ListFineAssessments(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	assessments, err := h.borrowingService.ListFineAssessments(id, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": assessments})
+}
+
+// Renew extends a borrowing by another loan period
+func (h *BorrowingHandler) Renew(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	borrowing, err := h.borrowingService.Renew(id, currentActor(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrowing})
+}
+
+// ListRenewals returns the renewal history of a borrowing
+func (h *BorrowingHandler) ListRenewals(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	renewals, err := h.borrowingService.ListRenewals(id, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": renewals})
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CirculationPolicyHandler exposes the circulation policy matrix to admins
+type CirculationPolicyHandler struct {
+	policyService *service.CirculationPolicyService
+}
+
+// NewCirculationPolicyHandler creates a new CirculationPolicyHandler instance
+func NewCirculationPolicyHandler(policyService *service.CirculationPolicyService) *CirculationPolicyHandler {
+	return &CirculationPolicyHandler{policyService: policyService}
+}
+
+// Register adds the circulation policy routes to the router group
+func (h *CirculationPolicyHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	policies := rg.Group("/admin/circulation-policies", auth.Require(models.PermPoliciesManage))
+	policies.GET("", h.List)
+	policies.PUT("", h.Save)
+	policies.DELETE("/:id", h.Delete)
+
+	closedDays := rg.Group("/admin/closed-days", auth.Require(models.PermPoliciesManage))
+	closedDays.GET("", h.ListClosedDays)
+	closedDays.PUT("", h.AddClosedDay)
+	closedDays.DELETE("/:date", h.DeleteClosedDay)
+}
+
+// closedDayRequest is the body for marking a closed day
+type closedDayRequest struct {
+	Date   string `json:"date" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// List returns every circulation policy
+func (h *CirculationPolicyHandler) List(c *gin.Context) {
+	policies, err := h.policyService.ListPolicies()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": policies})
+}
+
+// Save creates or replaces the policy for a role and category
+func (h *CirculationPolicyHandler) Save(c *gin.Context) {
+	var policy models.CirculationPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	if err := h.policyService.SavePolicy(&policy, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": policy})
+}
+
+// Delete removes a category-specific policy
+func (h *CirculationPolicyHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.policyService.DeletePolicy(id, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListClosedDays returns the closed days between the from and to dates.
+// Without a range the next twelve months are returned.
+func (h *CirculationPolicyHandler) ListClosedDays(c *gin.Context) {
+	from := time.Now()
+	to := from.AddDate(1, 0, 0)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			respondError(c, models.ErrInvalidClosedDay)
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			respondError(c, models.ErrInvalidClosedDay)
+			return
+		}
+		to = parsed
+	}
+
+	days, err := h.policyService.ListClosedDays(from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": days})
+}
+
+// AddClosedDay marks a date on which no fines accrue
+func (h *CirculationPolicyHandler) AddClosedDay(c *gin.Context) {
+	var req closedDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	date, err := time.Parse(dateLayout, req.Date)
+	if err != nil {
+		respondError(c, models.ErrInvalidClosedDay)
+		return
+	}
+
+	day := &models.ClosedDay{Date: date, Reason: req.Reason}
+	if err := h.policyService.AddClosedDay(day, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": day})
+}
+
+// DeleteClosedDay reopens a previously closed date
+func (h *CirculationPolicyHandler) DeleteClosedDay(c *gin.Context) {
+	date, err := time.Parse(dateLayout, c.Param("date"))
+	if err != nil {
+		respondError(c, models.ErrInvalidClosedDay)
+		return
+	}
+
+	if err := h.policyService.DeleteClosedDay(date, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dateLayout is the format of calendar dates in requests and query strings
+const dateLayout = "2006-01-02"
+
+// errorMapping ties a domain error to its HTTP status and machine readable code
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings lists the domain errors that are safe to show to clients
+var errorMappings = []errorMapping{
+	{models.ErrUnauthorized, http.StatusForbidden, "forbidden"},
+	{models.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{models.ErrBookNotFound, http.StatusNotFound, "book_not_found"},
+	{models.ErrBookCopyNotFound, http.StatusNotFound, "book_copy_not_found"},
+	{models.ErrDuplicateBarcode, http.StatusConflict, "duplicate_barcode"},
+	{models.ErrInvalidBarcode, http.StatusBadRequest, "invalid_barcode"},
+	{models.ErrInvalidCopyStatus, http.StatusBadRequest, "invalid_copy_status"},
+	{models.ErrBookCopyOnLoan, http.StatusConflict, "book_copy_on_loan"},
+	{models.ErrBookCopyRetired, http.StatusConflict, "book_copy_retired"},
+	{models.ErrBorrowingNotFound, http.StatusNotFound, "borrowing_not_found"},
+	{models.ErrPolicyNotFound, http.StatusNotFound, "policy_not_found"},
+	{models.ErrInvalidPolicy, http.StatusBadRequest, "invalid_policy"},
+	{models.ErrDefaultPolicyRequired, http.StatusConflict, "default_policy_required"},
+	{models.ErrInvalidClosedDay, http.StatusBadRequest, "invalid_closed_day"},
+	{models.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{models.ErrAmountExceedsBalance, http.StatusUnprocessableEntity, "amount_exceeds_balance"},
+	{models.ErrReceiptNotFound, http.StatusNotFound, "receipt_not_found"},
+	{models.ErrAccountSuspended, http.StatusForbidden, "account_suspended"},
+	{models.ErrAccountNotActive, http.StatusForbidden, "account_not_active"},
+	{models.ErrBorrowingAlreadyReturned, http.StatusConflict, "already_returned"},
+	{models.ErrMaxRenewalsReached, http.StatusConflict, "max_renewals_reached"},
+	{models.ErrTitleOnHold, http.StatusConflict, "title_on_hold"},
+	{models.ErrLoanOverdue, http.StatusConflict, "loan_overdue"},
+	{models.ErrCannotRenew, http.StatusConflict, "cannot_renew"},
+	{models.ErrReservationNotFound, http.StatusNotFound, "hold_not_found"},
+	{models.ErrAlreadyOnHold, http.StatusConflict, "already_on_hold"},
+	{models.ErrCopiesAvailable, http.StatusConflict, "copies_available"},
+	{models.ErrReservationClosed, http.StatusConflict, "hold_closed"},
+	{models.ErrUnknownNotificationType, http.StatusNotFound, "unknown_notification_type"},
+	{models.ErrNotificationRequired, http.StatusUnprocessableEntity, "notification_required"},
+	{models.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{models.ErrInvalidWebhook, http.StatusBadRequest, "invalid_webhook"},
+	{models.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{models.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{models.ErrTokenExpired, http.StatusUnauthorized, "token_expired"},
+	{models.ErrInvalidTwoFactorCode, http.StatusUnauthorized, "invalid_two_factor_code"},
+	{models.ErrTwoFactorAlreadyEnabled, http.StatusConflict, "two_factor_already_enabled"},
+	{models.ErrTwoFactorNotEnrolled, http.StatusConflict, "two_factor_not_enrolled"},
+	{models.ErrTwoFactorNotEnabled, http.StatusConflict, "two_factor_not_enabled"},
+	{models.ErrSessionNotFound, http.StatusNotFound, "session_not_found"},
+	{models.ErrRefreshTokenReused, http.StatusUnauthorized, "refresh_token_reused"},
+	{models.ErrAccountLocked, http.StatusLocked, "account_locked"},
+	{models.ErrTooManyLoginAttempts, http.StatusTooManyRequests, "too_many_login_attempts"},
+	{models.ErrSSODisabled, http.StatusNotFound, "sso_disabled"},
+	{models.ErrInvalidSSOState, http.StatusBadRequest, "invalid_sso_state"},
+	{models.ErrSSOLoginFailed, http.StatusBadGateway, "sso_login_failed"},
+	{models.ErrSSOEmailNotVerified, http.StatusForbidden, "sso_email_not_verified"},
+	{models.ErrInvalidAuthLogFilter, http.StatusBadRequest, "invalid_filter"},
+	{models.ErrInvalidExportFormat, http.StatusBadRequest, "invalid_export_format"},
+	{models.ErrUnknownEntityType, http.StatusNotFound, "unknown_entity_type"},
+	{models.ErrAccountHasOpenLoans, http.StatusConflict, "account_has_open_loans"},
+	{models.ErrAccountHasBalance, http.StatusConflict, "account_has_balance"},
+	{models.ErrAccountClosed, http.StatusConflict, "account_closed"},
+	{models.ErrRegistrationDisabled, http.StatusNotFound, "registration_disabled"},
+	{models.ErrInvalidRegistration, http.StatusBadRequest, "invalid_registration"},
+	{models.ErrEmailTaken, http.StatusConflict, "email_taken"},
+	{models.ErrPhoneTaken, http.StatusConflict, "phone_taken"},
+	{models.ErrInvalidPhone, http.StatusBadRequest, "invalid_phone"},
+	{models.ErrInvalidVerificationToken, http.StatusBadRequest, "invalid_verification_token"},
+	{models.ErrRegistrationNotPending, http.StatusConflict, "registration_not_pending"},
+	{models.ErrEmailNotVerified, http.StatusConflict, "email_not_verified"},
+}
+
+// currentUserID returns the authenticated user's ID, or 0 for anonymous requests
+func currentUserID(c *gin.Context) int64 {
+	return middleware.CurrentUserID(c)
+}
+
+// currentActor describes the authenticated user and the request for the
+// audit trail
+func currentActor(c *gin.Context) models.Actor {
+	return models.Actor{
+		UserID:    currentUserID(c),
+		IPAddress: c.ClientIP(),
+		RequestID: middleware.CurrentRequestID(c),
+	}
+}
+
+// parseIDParam reads a numeric path parameter and responds with 400 when it is malformed
+func parseIDParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_id", "error": "invalid " + name})
+		return 0, false
+	}
+	return id, true
+}
+
+// paginationParams reads the page and page_size query parameters.
+// Repositories clamp out of range values.
+func paginationParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	return page, pageSize
+}
+
+// respondError writes the JSON error response for err
+func respondError(c *gin.Context, err error) {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			c.JSON(mapping.status, gin.H{"code": mapping.code, "error": mapping.err.Error()})
+			return
+		}
+	}
+
+	c.Error(err)
+	c.JSON(http.StatusInternalServerError, gin.H{"code": "internal_error", "error": "internal server error"})
+}
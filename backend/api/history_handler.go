@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler exposes the audit trail of individual records
+type HistoryHandler struct {
+	historyService *service.HistoryService
+}
+
+// NewHistoryHandler creates a new HistoryHandler instance
+func NewHistoryHandler(historyService *service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+// Register adds the history routes to the router group
+func (h *HistoryHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	rg.GET("/history/:entityType/:id", auth.Require(models.PermAuditLogsRead), h.EntityHistory)
+}
+
+// EntityHistory returns the changes made to one book, book_copy, borrowing,
+// fine or user
+func (h *HistoryHandler) EntityHistory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	page, pageSize := paginationParams(c)
+	logs, total, err := h.historyService.EntityHistory(currentUserID(c), c.Param("entityType"), id, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      logs,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys that verify our tokens
+type JWKSHandler struct {
+	keyManager *service.KeyManager
+}
+
+// NewJWKSHandler creates a new JWKSHandler instance
+func NewJWKSHandler(keyManager *service.KeyManager) *JWKSHandler {
+	return &JWKSHandler{keyManager: keyManager}
+}
+
+// Register adds the key set route to the router group
+func (h *JWKSHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/.well-known/jwks.json", h.GetKeySet)
+}
+
+// GetKeySet returns the JSON Web Key Set. Verifiers may cache it briefly;
+// new keys are published well before they are used across instances.
+func (h *JWKSHandler) GetKeySet(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keyManager.JWKS())
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutHandler exposes the account lockout endpoints for admins
+type LockoutHandler struct {
+	lockoutService *service.LockoutService
+}
+
+// NewLockoutHandler creates a new LockoutHandler instance
+func NewLockoutHandler(lockoutService *service.LockoutService) *LockoutHandler {
+	return &LockoutHandler{lockoutService: lockoutService}
+}
+
+// Register adds the lockout routes to the router group
+func (h *LockoutHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	lockout := rg.Group("/admin/users/:id/lockout", auth.Require(models.PermUsersManage))
+	lockout.GET("", h.GetStatus)
+	lockout.DELETE("", h.Unlock)
+}
+
+// GetStatus returns a user's failed login state
+func (h *LockoutHandler) GetStatus(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	status, err := h.lockoutService.GetStatus(userID, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// Unlock lifts a user's lockout
+func (h *LockoutHandler) Unlock(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.lockoutService.Unlock(userID, currentUserID(c), c.ClientIP(), c.Request.UserAgent()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler exposes the notification preference endpoints
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// preferenceRequest is the body for changing a notification preference
+type preferenceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// Register adds the notification routes to the router group
+func (h *NotificationHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	preferences := rg.Group("/users/:id/notification-preferences", auth.RequireSelfOr("id", models.PermUsersManage))
+	preferences.GET("", h.ListPreferences)
+	preferences.PUT("/:type", h.UpdatePreference)
+}
+
+// ListPreferences returns a user's choice for every notification type
+func (h *NotificationHandler) ListPreferences(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	preferences, err := h.notificationService.ListPreferences(userID, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preferences})
+}
+
+// UpdatePreference turns a type of notification on or off
+func (h *NotificationHandler) UpdatePreference(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req preferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	err := h.notificationService.UpdatePreference(userID, c.Param("type"), *req.Enabled, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
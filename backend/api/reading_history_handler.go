@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingHistoryHandler exposes the reading history privacy setting
+type ReadingHistoryHandler struct {
+	readingHistoryService *service.ReadingHistoryService
+}
+
+// NewReadingHistoryHandler creates a new ReadingHistoryHandler instance
+func NewReadingHistoryHandler(readingHistoryService *service.ReadingHistoryService) *ReadingHistoryHandler {
+	return &ReadingHistoryHandler{readingHistoryService: readingHistoryService}
+}
+
+// readingHistoryRequest is the body for opting in to or out of reading history
+type readingHistoryRequest struct {
+	KeepHistory *bool `json:"keep_history" binding:"required"`
+}
+
+// Register adds the reading history routes to the router group
+func (h *ReadingHistoryHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	history := rg.Group("/users/:id/reading-history", auth.RequireSelfOr("id", models.PermUsersManage))
+	history.GET("", h.GetPreference)
+	history.PUT("", h.UpdatePreference)
+	history.DELETE("", h.ResetPreference)
+}
+
+// GetPreference returns whether a patron keeps their reading history
+func (h *ReadingHistoryHandler) GetPreference(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	preference, err := h.readingHistoryService.GetPreference(userID, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preference})
+}
+
+// UpdatePreference opts a patron in to or out of keeping their reading history
+func (h *ReadingHistoryHandler) UpdatePreference(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req readingHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	preference, err := h.readingHistoryService.SetPreference(userID, currentUserID(c), req.KeepHistory)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preference})
+}
+
+// ResetPreference returns a patron to the library's default for reading history
+func (h *ReadingHistoryHandler) ResetPreference(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	preference, err := h.readingHistoryService.SetPreference(userID, currentUserID(c), nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preference})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationHandler exposes patron self-registration and the staff
+// approval queue
+type RegistrationHandler struct {
+	registrationService *service.RegistrationService
+}
+
+// NewRegistrationHandler creates a new RegistrationHandler instance
+func NewRegistrationHandler(registrationService *service.RegistrationService) *RegistrationHandler {
+	return &RegistrationHandler{registrationService: registrationService}
+}
+
+// registrationRequest is the body for signing up
+type registrationRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+	Address  string `json:"address"`
+}
+
+// verifyEmailRequest is the body for confirming an email address
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// resendVerificationRequest is the body for asking for a new verification link
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// Register adds the registration routes to the router group
+func (h *RegistrationHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	rg.POST("/auth/register", h.SignUp)
+	rg.POST("/auth/verify-email", h.VerifyEmail)
+	rg.POST("/auth/verify-email/resend", h.ResendVerification)
+
+	queue := rg.Group("/admin/registrations", auth.Require(models.PermUsersManage))
+	queue.GET("", h.ListPending)
+	queue.POST("/:id/approve", h.Approve)
+	queue.POST("/:id/reject", h.Reject)
+}
+
+// SignUp creates a pending account and emails a verification link
+func (h *RegistrationHandler) SignUp(c *gin.Context) {
+	var req registrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	status, err := h.registrationService.Register(models.Registration{
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
+		Phone:    req.Phone,
+		Address:  req.Address,
+	}, currentActor(c), c.Request.UserAgent())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": status})
+}
+
+// VerifyEmail confirms an email address with the token from the link
+func (h *RegistrationHandler) VerifyEmail(c *gin.Context) {
+	var req verifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	status, err := h.registrationService.VerifyEmail(req.Token, currentActor(c), c.Request.UserAgent())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// ResendVerification emails a new verification link. It always answers the
+// same way, whether or not the address is registered.
+func (h *RegistrationHandler) ResendVerification(c *gin.Context) {
+	var req resendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	if err := h.registrationService.ResendVerification(req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ListPending returns a page of verified accounts waiting for approval
+func (h *RegistrationHandler) ListPending(c *gin.Context) {
+	page, pageSize := paginationParams(c)
+	registrations, total, err := h.registrationService.ListPending(currentUserID(c), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      registrations,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Approve activates a pending account
+func (h *RegistrationHandler) Approve(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.registrationService.Approve(userID, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reject turns down a pending registration
+func (h *RegistrationHandler) Reject(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.registrationService.Reject(userID, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
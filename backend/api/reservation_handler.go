@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservationHandler exposes the hold queue endpoints
+type ReservationHandler struct {
+	reservationService *service.ReservationService
+}
+
+// NewReservationHandler creates a new ReservationHandler instance
+func NewReservationHandler(reservationService *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{reservationService: reservationService}
+}
+
+// Register adds the hold queue routes to the router group
+func (h *ReservationHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	rg.POST("/books/:id/holds", auth.Authenticated(), h.PlaceHold)
+	rg.GET("/books/:id/holds", auth.Require(models.PermHoldsManage), h.ListQueue)
+	rg.GET("/users/:id/holds", auth.RequireSelfOr("id", models.PermHoldsManage), h.ListUserHolds)
+	rg.GET("/holds/:id", auth.Authenticated(), h.GetHold)
+	rg.DELETE("/holds/:id", auth.Authenticated(), h.CancelHold)
+}
+
+// PlaceHold puts the current user in the hold queue for a book
+func (h *ReservationHandler) PlaceHold(c *gin.Context) {
+	bookID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.reservationService.PlaceHold(currentUserID(c), bookID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": reservation})
+}
+
+// ListQueue returns the hold queue for a book
+func (h *ReservationHandler) ListQueue(c *gin.Context) {
+	bookID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reservations, err := h.reservationService.ListQueue(bookID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reservations})
+}
+
+// ListUserHolds returns a patron's open holds
+func (h *ReservationHandler) ListUserHolds(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reservations, err := h.reservationService.ListUserHolds(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reservations})
+}
+
+// GetHold returns a hold and its queue position
+func (h *ReservationHandler) GetHold(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.reservationService.GetHold(id, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reservation})
+}
+
+// CancelHold removes a hold from the queue
+func (h *ReservationHandler) CancelHold(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.reservationService.CancelHold(id, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
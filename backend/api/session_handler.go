@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler exposes the active session endpoints
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// Register adds the session routes to the router group
+func (h *SessionHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	sessions := rg.Group("/users/:id/sessions", auth.RequireSelfOr("id", models.PermUsersManage))
+	sessions.GET("", h.List)
+	sessions.DELETE("/:sessionId", h.Revoke)
+}
+
+// List returns a user's active sessions
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(userID, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+// Revoke signs a user out of one session
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	sessionID, ok := parseIDParam(c, "sessionId")
+	if !ok {
+		return
+	}
+
+	err := h.sessionService.RevokeSession(userID, sessionID, currentUserID(c), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSOHandler exposes single sign-on through the campus identity provider
+type SSOHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOHandler creates a new SSOHandler instance
+func NewSSOHandler(ssoService *service.SSOService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService}
+}
+
+// ssoCallbackRequest is the body the client posts after the provider
+// redirected back to it
+type ssoCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// Register adds the single sign-on routes to the router group
+func (h *SSOHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/auth/oidc/login", h.BeginLogin)
+	rg.POST("/auth/oidc/callback", h.CompleteLogin)
+}
+
+// BeginLogin redirects the browser to the identity provider. Clients that
+// ask for JSON get the address instead.
+func (h *SSOHandler) BeginLogin(c *gin.Context) {
+	authURL, err := h.ssoService.BeginLogin()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"authorization_url": authURL}})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// CompleteLogin exchanges the code from the provider for access tokens, or
+// an MFA token when the account has two-factor authentication
+func (h *SSOHandler) CompleteLogin(c *gin.Context) {
+	var req ssoCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	result, err := h.ssoService.CompleteLogin(req.State, req.Code, currentActor(c), c.Request.UserAgent())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
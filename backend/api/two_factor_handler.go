@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler exposes two-factor enrollment and the second login step
+type TwoFactorHandler struct {
+	authService      *service.AuthService
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new TwoFactorHandler instance
+func NewTwoFactorHandler(authService *service.AuthService, twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{authService: authService, twoFactorService: twoFactorService}
+}
+
+// twoFactorCodeRequest is the body of requests confirmed with a code
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// twoFactorLoginRequest is the body for the second login step
+type twoFactorLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// Register adds the two-factor routes to the router group
+func (h *TwoFactorHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	rg.POST("/auth/login/2fa", h.CompleteLogin)
+
+	twoFactor := rg.Group("/auth/2fa", auth.Authenticated())
+	twoFactor.POST("/enrollment", h.BeginEnrollment)
+	twoFactor.POST("/enrollment/confirm", h.ConfirmEnrollment)
+	twoFactor.POST("/disable", h.Disable)
+	twoFactor.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+}
+
+// CompleteLogin exchanges an MFA token and a code for access tokens
+func (h *TwoFactorHandler) CompleteLogin(c *gin.Context) {
+	var req twoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.CompleteTwoFactorLogin(req.MFAToken, req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// BeginEnrollment creates a new authenticator secret for the current user
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	enrollment, err := h.twoFactorService.BeginEnrollment(currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": enrollment})
+}
+
+// ConfirmEnrollment enables two-factor authentication and returns the
+// recovery codes
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	// The codes are only shown once, so return them even when the notice
+	// about the change could not be sent
+	codes, err := h.twoFactorService.ConfirmEnrollment(currentUserID(c), req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil && codes == nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"recovery_codes": codes}})
+}
+
+// Disable turns off two-factor authentication for the current user
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	if err := h.twoFactorService.Disable(currentUserID(c), req.Code, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(currentUserID(c), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"recovery_codes": codes}})
+}
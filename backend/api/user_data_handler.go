@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserDataHandler exposes account closure and personal data exports
+type UserDataHandler struct {
+	userDataService *service.UserDataService
+}
+
+// NewUserDataHandler creates a new UserDataHandler instance
+func NewUserDataHandler(userDataService *service.UserDataService) *UserDataHandler {
+	return &UserDataHandler{userDataService: userDataService}
+}
+
+// Register adds the account closure and data export routes to the router group
+func (h *UserDataHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	users := rg.Group("/users/:id", auth.RequireSelfOr("id", models.PermUsersManage))
+	users.POST("/close", h.CloseAccount)
+	users.GET("/export", h.ExportData)
+}
+
+// CloseAccount anonymizes a patron's account once their loans are returned
+// and their balance is settled
+func (h *UserDataHandler) CloseAccount(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userDataService.CloseAccount(userID, currentActor(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportData downloads everything held about a patron as a JSON document
+func (h *UserDataHandler) ExportData(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	export, err := h.userDataService.ExportData(userID, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filename := "user-" + strconv.FormatInt(userID, 10) + "-data.json"
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.JSON(http.StatusOK, gin.H{"data": export})
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+
+	"library-management-system/internal/middleware"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler exposes webhook subscription management to admins
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// Register adds the webhook routes to the router group
+func (h *WebhookHandler) Register(rg *gin.RouterGroup, auth *middleware.Authorizer) {
+	webhooks := rg.Group("/admin/webhooks", auth.Require(models.PermWebhooksManage))
+	webhooks.GET("", h.List)
+	webhooks.POST("", h.Create)
+	webhooks.GET("/:id", h.Get)
+	webhooks.PUT("/:id", h.Update)
+	webhooks.DELETE("/:id", h.Delete)
+	webhooks.GET("/:id/deliveries", h.ListDeliveries)
+}
+
+// webhookRequest is the body for creating or updating a webhook
+type webhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// List returns every webhook
+func (h *WebhookHandler) List(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": webhooks})
+}
+
+// Create registers a webhook and returns it with its signing secret
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	webhook := &models.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}
+
+	if err := h.webhookService.CreateWebhook(webhook, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": webhook})
+}
+
+// Get returns a single webhook
+func (h *WebhookHandler) Get(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(id, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": webhook})
+}
+
+// Update changes a webhook's address, event filter or active flag.
+// Setting active to true re-enables an endpoint that was disabled.
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "error": err.Error()})
+		return
+	}
+
+	webhook := &models.Webhook{
+		ID:         id,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Active:     req.Active == nil || *req.Active,
+	}
+
+	updated, err := h.webhookService.UpdateWebhook(webhook, currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// Delete removes a webhook
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(id, currentUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns a webhook's delivery log
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	page, pageSize := paginationParams(c)
+	deliveries, total, err := h.webhookService.ListDeliveries(id, currentUserID(c), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      deliveries,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
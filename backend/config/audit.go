@@ -0,0 +1,21 @@
+package config
+
+// AuditConfig holds settings for the security audit log API
+type AuditConfig struct {
+	// ExportMaxRows caps the rows written by one export
+	ExportMaxRows int
+
+	// FailureBurstThreshold failed logins from one address within
+	// AnomalyWindowHours are reported as an anomaly
+	FailureBurstThreshold int
+	AnomalyWindowHours    int
+}
+
+// LoadAuditConfig reads the audit log settings from the environment
+func LoadAuditConfig() AuditConfig {
+	return AuditConfig{
+		ExportMaxRows:         envInt("AUDIT_EXPORT_MAX_ROWS", 100000),
+		FailureBurstThreshold: envInt("AUDIT_FAILURE_BURST_THRESHOLD", 10),
+		AnomalyWindowHours:    envInt("AUDIT_ANOMALY_WINDOW_HOURS", 24),
+	}
+}
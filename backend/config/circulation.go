@@ -0,0 +1,15 @@
+package config
+
+// CirculationConfig holds settings for the background circulation jobs
+type CirculationConfig struct {
+	SweepIntervalMinutes int
+	LostAfterDays        int
+}
+
+// LoadCirculationConfig reads the circulation settings from the environment
+func LoadCirculationConfig() CirculationConfig {
+	return CirculationConfig{
+		SweepIntervalMinutes: envInt("CIRCULATION_SWEEP_INTERVAL_MINUTES", 60),
+		LostAfterDays:        envInt("CIRCULATION_LOST_AFTER_DAYS", 60),
+	}
+}
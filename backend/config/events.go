@@ -0,0 +1,25 @@
+package config
+
+// EventsConfig holds settings for delivering domain events from the outbox
+type EventsConfig struct {
+	OutboxPollIntervalSeconds int
+	OutboxBatchSize           int
+
+	WebhookPollIntervalSeconds int
+	WebhookTimeoutSeconds      int
+	WebhookMaxAttempts         int
+	WebhookDisableAfter        int
+}
+
+// LoadEventsConfig reads the event delivery settings from the environment
+func LoadEventsConfig() EventsConfig {
+	return EventsConfig{
+		OutboxPollIntervalSeconds: envInt("OUTBOX_POLL_INTERVAL_SECONDS", 5),
+		OutboxBatchSize:           envInt("OUTBOX_BATCH_SIZE", 100),
+
+		WebhookPollIntervalSeconds: envInt("WEBHOOK_POLL_INTERVAL_SECONDS", 10),
+		WebhookTimeoutSeconds:      envInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+		WebhookMaxAttempts:         envInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookDisableAfter:        envInt("WEBHOOK_DISABLE_AFTER_FAILURES", 20),
+	}
+}
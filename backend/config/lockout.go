@@ -0,0 +1,29 @@
+package config
+
+// LockoutConfig holds settings for account lockout and login throttling
+type LockoutConfig struct {
+	// MaxFailedAttempts is how many wrong passwords or codes in a row lock
+	// an account
+	MaxFailedAttempts int
+
+	// BaseLockoutMinutes is the length of the first lockout. Each further
+	// lockout before a successful login doubles it, up to MaxLockoutMinutes.
+	BaseLockoutMinutes int
+	MaxLockoutMinutes  int
+
+	// IPMaxAttempts failed logins from one address within IPWindowMinutes
+	// block further logins from it until the window passes
+	IPMaxAttempts   int
+	IPWindowMinutes int
+}
+
+// LoadLockoutConfig reads the lockout settings from the environment
+func LoadLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		MaxFailedAttempts:  envInt("LOCKOUT_MAX_FAILED_ATTEMPTS", 5),
+		BaseLockoutMinutes: envInt("LOCKOUT_BASE_MINUTES", 15),
+		MaxLockoutMinutes:  envInt("LOCKOUT_MAX_MINUTES", 1440),
+		IPMaxAttempts:      envInt("LOGIN_IP_MAX_ATTEMPTS", 20),
+		IPWindowMinutes:    envInt("LOGIN_IP_WINDOW_MINUTES", 15),
+	}
+}
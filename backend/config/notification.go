@@ -0,0 +1,31 @@
+package config
+
+// NotificationConfig holds settings for outgoing patron notifications
+type NotificationConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FromAddress  string
+	FromName     string
+
+	// AppBaseURL is the frontend address used to build links in messages
+	AppBaseURL string
+
+	// DueSoonDays is how many days before the due date reminders go out
+	DueSoonDays int
+}
+
+// LoadNotificationConfig reads the notification settings from the environment
+func LoadNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		SMTPHost:     envString("SMTP_HOST", "localhost"),
+		SMTPPort:     envInt("SMTP_PORT", 25),
+		SMTPUsername: envString("SMTP_USERNAME", ""),
+		SMTPPassword: envString("SMTP_PASSWORD", ""),
+		FromAddress:  envString("MAIL_FROM_ADDRESS", "library@localhost"),
+		FromName:     envString("MAIL_FROM_NAME", "Library"),
+		AppBaseURL:   envString("APP_BASE_URL", "http://localhost:3000"),
+		DueSoonDays:  envInt("NOTIFY_DUE_SOON_DAYS", 2),
+	}
+}
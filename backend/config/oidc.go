@@ -0,0 +1,50 @@
+package config
+
+import "strings"
+
+// OIDCConfig holds settings for single sign-on with the campus identity
+// provider. Pointing IssuerURL at a local mock provider is enough to test
+// the whole flow, since every endpoint is discovered from the issuer.
+type OIDCConfig struct {
+	Enabled      bool
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the frontend page that receives the authorization code
+	// and posts it to the callback endpoint
+	RedirectURL string
+	Scopes      []string
+
+	// RoleClaim names the ID token claim listing the user's groups. Members
+	// of StaffGroups or AdminGroups get the matching role; everyone else is
+	// a member.
+	RoleClaim   string
+	StaffGroups []string
+	AdminGroups []string
+
+	// SyncRoles updates the role from the claims on every login instead of
+	// only when the account is created
+	SyncRoles bool
+
+	StateLifetimeMinutes int
+}
+
+// LoadOIDCConfig reads the single sign-on settings from the environment
+func LoadOIDCConfig() OIDCConfig {
+	return OIDCConfig{
+		Enabled:      envBool("OIDC_ENABLED", false),
+		IssuerURL:    strings.TrimRight(envString("OIDC_ISSUER_URL", ""), "/"),
+		ClientID:     envString("OIDC_CLIENT_ID", ""),
+		ClientSecret: envString("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  envString("OIDC_REDIRECT_URL", "http://localhost:3000/auth/callback"),
+		Scopes:       envList("OIDC_SCOPES", []string{"openid", "email", "profile"}),
+
+		RoleClaim:   envString("OIDC_ROLE_CLAIM", "groups"),
+		StaffGroups: envList("OIDC_STAFF_GROUPS", nil),
+		AdminGroups: envList("OIDC_ADMIN_GROUPS", nil),
+		SyncRoles:   envBool("OIDC_SYNC_ROLES", false),
+
+		StateLifetimeMinutes: envInt("OIDC_STATE_LIFETIME_MINUTES", 10),
+	}
+}
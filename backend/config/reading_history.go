@@ -0,0 +1,23 @@
+package config
+
+// ReadingHistoryConfig holds settings for keeping patrons' returned loans
+type ReadingHistoryConfig struct {
+	// KeepByDefault applies to patrons who have not opted in or out
+	KeepByDefault bool
+
+	// RetentionDays returned loans of patrons who do not keep their history
+	// stay linked to them before the purge removes the link
+	RetentionDays        int
+	PurgeIntervalMinutes int
+	PurgeBatchSize       int
+}
+
+// LoadReadingHistoryConfig reads the reading history settings from the environment
+func LoadReadingHistoryConfig() ReadingHistoryConfig {
+	return ReadingHistoryConfig{
+		KeepByDefault:        envBool("READING_HISTORY_KEEP_BY_DEFAULT", false),
+		RetentionDays:        envInt("READING_HISTORY_RETENTION_DAYS", 30),
+		PurgeIntervalMinutes: envInt("READING_HISTORY_PURGE_INTERVAL_MINUTES", 60),
+		PurgeBatchSize:       envInt("READING_HISTORY_PURGE_BATCH_SIZE", 500),
+	}
+}
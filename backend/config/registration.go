@@ -0,0 +1,25 @@
+package config
+
+// RegistrationConfig holds settings for patron self-registration
+type RegistrationConfig struct {
+	Enabled bool
+
+	// RequireApproval keeps verified accounts pending until staff approve them
+	RequireApproval bool
+
+	VerificationLifetimeHours int
+
+	// ExpiryIntervalMinutes is how often pending accounts that were never
+	// verified are removed once their last link has expired
+	ExpiryIntervalMinutes int
+}
+
+// LoadRegistrationConfig reads the registration settings from the environment
+func LoadRegistrationConfig() RegistrationConfig {
+	return RegistrationConfig{
+		Enabled:                   envBool("REGISTRATION_ENABLED", true),
+		RequireApproval:           envBool("REGISTRATION_REQUIRE_APPROVAL", false),
+		VerificationLifetimeHours: envInt("REGISTRATION_VERIFICATION_LIFETIME_HOURS", 48),
+		ExpiryIntervalMinutes:     envInt("REGISTRATION_EXPIRY_INTERVAL_MINUTES", 60),
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+// SessionConfig holds settings for login sessions
+type SessionConfig struct {
+	// NotifyOnTokenReuse emails users when a session is revoked because one
+	// of its refresh tokens was presented a second time
+	NotifyOnTokenReuse bool
+}
+
+// LoadSessionConfig reads the session settings from the environment
+func LoadSessionConfig() SessionConfig {
+	return SessionConfig{
+		NotifyOnTokenReuse: envBool("SESSION_NOTIFY_ON_TOKEN_REUSE", true),
+	}
+}
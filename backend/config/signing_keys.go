@@ -0,0 +1,34 @@
+package config
+
+// SigningKeyConfig holds settings for the asymmetric JWT signing keys
+type SigningKeyConfig struct {
+	// Algorithm is RS256 or EdDSA
+	Algorithm string
+
+	// EncryptionKey protects the private keys at rest
+	EncryptionKey string
+
+	// RotationDays is how long a key signs new tokens before it is replaced
+	RotationDays int
+
+	// CheckIntervalMinutes is how often keys are reloaded and rotation is
+	// checked, which also picks up keys rotated by other instances
+	CheckIntervalMinutes int
+
+	// LegacyRolloutAt is when asymmetric signing went live, in RFC 3339.
+	// HS256 tokens issued before it are accepted until the longest token
+	// lifetime has passed. When empty, the creation of the first signing
+	// key is taken as the rollout time.
+	LegacyRolloutAt string
+}
+
+// LoadSigningKeyConfig reads the signing key settings from the environment
+func LoadSigningKeyConfig() SigningKeyConfig {
+	return SigningKeyConfig{
+		Algorithm:            envString("JWT_SIGNING_ALGORITHM", "EdDSA"),
+		EncryptionKey:        envString("JWT_KEY_ENCRYPTION_KEY", ""),
+		RotationDays:         envInt("JWT_KEY_ROTATION_DAYS", 30),
+		CheckIntervalMinutes: envInt("JWT_KEY_CHECK_INTERVAL_MINUTES", 10),
+		LegacyRolloutAt:      envString("JWT_LEGACY_ROLLOUT_AT", ""),
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+// TwoFactorConfig holds settings for TOTP two-factor authentication
+type TwoFactorConfig struct {
+	// Issuer is the account label shown in authenticator apps
+	Issuer string
+
+	// EncryptionKey protects TOTP secrets at rest
+	EncryptionKey string
+
+	// MFATokenLifetimeMinutes is how long a user has to enter a code after
+	// their password was accepted
+	MFATokenLifetimeMinutes int
+
+	// RecoveryCodeCount is how many one-time recovery codes are issued
+	RecoveryCodeCount int
+}
+
+// LoadTwoFactorConfig reads the two-factor settings from the environment
+func LoadTwoFactorConfig() TwoFactorConfig {
+	return TwoFactorConfig{
+		Issuer:                  envString("TWO_FACTOR_ISSUER", "Library"),
+		EncryptionKey:           envString("TWO_FACTOR_ENCRYPTION_KEY", ""),
+		MFATokenLifetimeMinutes: envInt("MFA_TOKEN_LIFETIME_MINUTES", 5),
+		RecoveryCodeCount:       envInt("TWO_FACTOR_RECOVERY_CODES", 10),
+	}
+}
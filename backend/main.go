@@ -52,20 +52,115 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	bookRepo := repository.NewBookRepository(db)
+	bookCopyRepo := repository.NewBookCopyRepository(db)
 	borrowingRepo := repository.NewBorrowingRepository(db)
 	authLogRepo := repository.NewAuthLogRepository(db)
 	tokenRepo := repository.NewTokenRepository(db)
+	policyRepo := repository.NewCirculationPolicyRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	calendarRepo := repository.NewLibraryCalendarRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	activityRepo := repository.NewActivityLogRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	registrationRepo := repository.NewRegistrationRepository(db)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, authLogRepo, tokenRepo, cfg.Auth)
+	notificationCfg := config.LoadNotificationConfig()
+	notificationService := service.NewNotificationService(
+		notificationRepo, borrowingRepo, userRepo, notificationCfg, logger,
+		service.NewSMTPChannel(notificationCfg),
+	)
+	twoFactorService, err := service.NewTwoFactorService(
+		userRepo, twoFactorRepo, authLogRepo, notificationService, config.LoadTwoFactorConfig(),
+	)
+	if err != nil {
+		logger.Fatal("Failed to initialize two-factor authentication", "error", err)
+	}
+	sessionService := service.NewSessionService(
+		sessionRepo, userRepo, authLogRepo, notificationService, config.LoadSessionConfig(),
+	)
+	lockoutService := service.NewLockoutService(db, userRepo, authLogRepo, config.LoadLockoutConfig())
+	keyManager, err := service.NewKeyManager(
+		signingKeyRepo, logger, config.LoadSigningKeyConfig(), cfg.Auth.JWTSecret,
+		time.Duration(cfg.Auth.RefreshTokenLifetimeDays)*24*time.Hour,
+	)
+	if err != nil {
+		logger.Fatal("Failed to load JWT signing keys", "error", err)
+	}
+	authService := service.NewAuthService(
+		db, userRepo, authLogRepo, tokenRepo, sessionRepo, activityRepo, sessionService, notificationService,
+		twoFactorService, lockoutService, keyManager, logger, cfg.Auth,
+	)
+	ssoService := service.NewSSOService(
+		db, userRepo, identityRepo, activityRepo, authService, &http.Client{Timeout: 10 * time.Second}, logger,
+		config.LoadOIDCConfig(),
+	)
+	auditCfg := config.LoadAuditConfig()
+	authLogService := service.NewAuthLogService(authLogRepo, userRepo, auditCfg)
+	historyService := service.NewHistoryService(activityRepo, userRepo)
 	userService := service.NewUserService(userRepo)
-	bookService := service.NewBookService(bookRepo)
-	borrowingService := service.NewBorrowingService(borrowingRepo, bookRepo, userRepo)
+	bookService := service.NewBookService(db, bookRepo, activityRepo)
+	bookCopyService := service.NewBookCopyService(db, bookCopyRepo, bookRepo, activityRepo)
+	reservationService := service.NewReservationService(
+		db, reservationRepo, borrowingRepo, bookRepo, userRepo, notificationService,
+	)
+	borrowingService := service.NewBorrowingService(
+		db, borrowingRepo, bookRepo, bookCopyRepo, userRepo, policyRepo, fineRepo, calendarRepo, ledgerRepo,
+		activityRepo, reservationService,
+	)
+	policyService := service.NewCirculationPolicyService(policyRepo, calendarRepo, userRepo)
+	accountService := service.NewAccountService(db, ledgerRepo, borrowingRepo, userRepo, activityRepo)
+	userDataService := service.NewUserDataService(
+		db, userRepo, borrowingRepo, fineRepo, ledgerRepo, reservationRepo, authLogRepo, activityRepo,
+		reservationService, auditCfg,
+	)
+	registrationService := service.NewRegistrationService(
+		db, userRepo, registrationRepo, authLogRepo, activityRepo, notificationService, logger,
+		config.LoadRegistrationConfig(),
+	)
+	readingHistoryService := service.NewReadingHistoryService(
+		userRepo, borrowingRepo, logger, config.LoadReadingHistoryConfig(),
+	)
+
+	// Initialize background jobs
+	circulationCfg := config.LoadCirculationConfig()
+	overdueSweeper := service.NewOverdueSweeper(
+		db, borrowingRepo, bookCopyRepo, policyRepo, fineRepo, calendarRepo, activityRepo,
+		reservationService, notificationService, logger,
+		time.Duration(circulationCfg.SweepIntervalMinutes)*time.Minute,
+		circulationCfg.LostAfterDays,
+	)
+	eventsCfg := config.LoadEventsConfig()
+	outboxDispatcher := service.NewOutboxDispatcher(
+		outboxRepo, logger,
+		time.Duration(eventsCfg.OutboxPollIntervalSeconds)*time.Second,
+		eventsCfg.OutboxBatchSize,
+	)
+	webhookService := service.NewWebhookService(
+		webhookRepo, userRepo,
+		&http.Client{Timeout: time.Duration(eventsCfg.WebhookTimeoutSeconds) * time.Second},
+		logger,
+		service.WebhookOptions{
+			PollInterval: time.Duration(eventsCfg.WebhookPollIntervalSeconds) * time.Second,
+			BatchSize:    eventsCfg.OutboxBatchSize,
+			MaxAttempts:  eventsCfg.WebhookMaxAttempts,
+			DisableAfter: eventsCfg.WebhookDisableAfter,
+		},
+	)
+	outboxDispatcher.Subscribe(webhookService)
 
 	// Initialize router
 	router := gin.New()
 
 	// Apply middleware
+	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.CORS(cfg.CORS))
@@ -75,6 +170,28 @@ func main() {
 	// Initialize API handlers
 	api.RegisterRoutes(router, authService, userService, bookService, borrowingService, cfg)
 
+	authorizer := middleware.NewAuthorizer(authService)
+
+	api.NewJWKSHandler(keyManager).Register(&router.RouterGroup)
+
+	v1 := router.Group("/api/v1")
+	api.NewBookCopyHandler(bookCopyService).Register(v1, authorizer)
+	api.NewCirculationPolicyHandler(policyService).Register(v1, authorizer)
+	api.NewBorrowingHandler(borrowingService).Register(v1, authorizer)
+	api.NewAccountHandler(accountService).Register(v1, authorizer)
+	api.NewReservationHandler(reservationService).Register(v1, authorizer)
+	api.NewNotificationHandler(notificationService).Register(v1, authorizer)
+	api.NewWebhookHandler(webhookService).Register(v1, authorizer)
+	api.NewTwoFactorHandler(authService, twoFactorService).Register(v1, authorizer)
+	api.NewSessionHandler(sessionService).Register(v1, authorizer)
+	api.NewLockoutHandler(lockoutService).Register(v1, authorizer)
+	api.NewAuthLogHandler(authLogService).Register(v1, authorizer)
+	api.NewHistoryHandler(historyService).Register(v1, authorizer)
+	api.NewUserDataHandler(userDataService).Register(v1, authorizer)
+	api.NewReadingHistoryHandler(readingHistoryService).Register(v1, authorizer)
+	api.NewRegistrationHandler(registrationService).Register(v1, authorizer)
+	api.NewSSOHandler(ssoService).Register(v1)
+
 	// Setup HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
@@ -92,6 +209,14 @@ func main() {
 		}
 	}()
 
+	// Start background jobs
+	overdueSweeper.Start()
+	outboxDispatcher.Start()
+	webhookService.Start()
+	keyManager.Start()
+	readingHistoryService.Start()
+	registrationService.Start()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -108,5 +233,13 @@ func main() {
 		logger.Fatal("Server forced to shutdown", "error", err)
 	}
 
+	// Stop background jobs
+	overdueSweeper.Stop()
+	outboxDispatcher.Stop()
+	webhookService.Stop()
+	keyManager.Stop()
+	readingHistoryService.Stop()
+	registrationService.Stop()
+
 	logger.Info("Server exited gracefully")
 }
@@ -1,33 +1,85 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
 )
 
-func JWTAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+// JWTAuth rejects requests without a valid access token and stores the
+// authenticated user in the context
+func JWTAuth(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := authenticate(c, authService); !ok {
 			return
 		}
+		c.Next()
+	}
+}
 
-		tokenString := strings.Split(authHeader, " ")[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+// OptionalAuth stores the authenticated user in the context when the request
+// carries a valid access token and lets anonymous requests through. A token
+// that is present but invalid is still rejected.
+func OptionalAuth(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
+		if _, ok := authenticate(c, authService); !ok {
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate resolves the user behind the request's bearer token and stores
+// it in the context. It aborts the request and returns false when there is none.
+func authenticate(c *gin.Context, authService *service.AuthService) (*models.User, bool) {
+	if user := CurrentUser(c); user != nil {
+		return user, true
+	}
+
+	token, ok := bearerToken(c.GetHeader("Authorization"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": "unauthorized", "error": "a bearer token is required"})
+		return nil, false
+	}
+
+	user, err := authService.ValidateToken(token)
+	if err != nil {
+		abortAuthError(c, err)
+		return nil, false
+	}
+
+	SetCurrentUser(c, user)
+	return user, true
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
 
-		// Token is valid, proceed
-		next.ServeHTTP(w, r)
-	})
+// abortAuthError writes the JSON error response for a rejected token
+func abortAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrTokenExpired):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": "token_expired", "error": "access token has expired"})
+	case errors.Is(err, models.ErrAccountNotActive):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": "account_not_active", "error": models.ErrAccountNotActive.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": "invalid_token", "error": "invalid access token"})
+	}
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"library-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys holding the authenticated user
+const (
+	ContextUserKey   = "user"
+	ContextUserIDKey = "userID"
+)
+
+// SetCurrentUser stores the authenticated user in the request context
+func SetCurrentUser(c *gin.Context, user *models.User) {
+	c.Set(ContextUserKey, user)
+	c.Set(ContextUserIDKey, user.ID)
+}
+
+// CurrentUser returns the authenticated user, or nil for anonymous requests
+func CurrentUser(c *gin.Context) *models.User {
+	value, ok := c.Get(ContextUserKey)
+	if !ok {
+		return nil
+	}
+
+	user, _ := value.(*models.User)
+	return user
+}
+
+// CurrentUserID returns the authenticated user's ID, or 0 for anonymous requests
+func CurrentUserID(c *gin.Context) int64 {
+	return c.GetInt64(ContextUserIDKey)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorizer builds gin middleware that authenticates requests and checks
+// the permissions each route declares
+type Authorizer struct {
+	authService *service.AuthService
+}
+
+// NewAuthorizer creates a new Authorizer instance
+func NewAuthorizer(authService *service.AuthService) *Authorizer {
+	return &Authorizer{authService: authService}
+}
+
+// Authenticated only lets requests with a valid access token through
+func (a *Authorizer) Authenticated() gin.HandlerFunc {
+	return JWTAuth(a.authService)
+}
+
+// Optional identifies the user when a token is sent and lets anonymous
+// requests through
+func (a *Authorizer) Optional() gin.HandlerFunc {
+	return OptionalAuth(a.authService)
+}
+
+// Require lets a request through when the user's role grants every given
+// permission
+func (a *Authorizer) Require(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := a.authenticate(c)
+		if !ok {
+			return
+		}
+
+		if !models.HasAllPermissions(user.Role, permissions...) {
+			abortForbidden(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOr lets users through to routes about themselves, identified by
+// the named path parameter, and everyone else only with the permissions
+func (a *Authorizer) RequireSelfOr(param string, permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := a.authenticate(c)
+		if !ok {
+			return
+		}
+
+		if id, err := strconv.ParseInt(c.Param(param), 10, 64); err == nil && id == user.ID {
+			c.Next()
+			return
+		}
+
+		if !models.HasAllPermissions(user.Role, permissions...) {
+			abortForbidden(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticate resolves the request's user through the AuthService
+func (a *Authorizer) authenticate(c *gin.Context) (*models.User, bool) {
+	return authenticate(c, a.authService)
+}
+
+// abortForbidden rejects a request whose user lacks a permission
+func abortForbidden(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": "forbidden", "error": models.ErrUnauthorized.Error()})
+}
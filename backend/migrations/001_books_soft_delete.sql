@@ -0,0 +1,6 @@
+-- Soft delete for catalog records so borrowing history keeps its book references
+ALTER TABLE books
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at),
+    ADD INDEX idx_language (language),
+    ADD INDEX idx_publication_year (publication_year);
@@ -0,0 +1,18 @@
+-- Item-level inventory: barcodes, shelving location and retirement of copies
+ALTER TABLE book_copies
+    ADD COLUMN barcode VARCHAR(64) NULL AFTER copy_number,
+    ADD COLUMN location VARCHAR(100) NULL AFTER status,
+    ADD COLUMN retired_at TIMESTAMP NULL AFTER notes,
+    MODIFY COLUMN status ENUM('available', 'borrowed', 'reserved', 'lost', 'damaged', 'in_repair', 'withdrawn') DEFAULT 'available',
+    ADD UNIQUE KEY uq_barcode (barcode);
+
+-- Bring the cached counters on books in line with the copy rows
+UPDATE books b
+SET b.total_copies = (
+        SELECT COUNT(*) FROM book_copies bc
+        WHERE bc.book_id = b.id AND bc.status NOT IN ('lost', 'damaged', 'withdrawn')
+    ),
+    b.available_copies = (
+        SELECT COUNT(*) FROM book_copies bc
+        WHERE bc.book_id = b.id AND bc.status = 'available'
+    );
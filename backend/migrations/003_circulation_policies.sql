@@ -0,0 +1,25 @@
+-- Lending rules per user role and book category.
+-- An empty category is the fallback for the role.
+CREATE TABLE circulation_policies (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_role VARCHAR(20) NOT NULL,
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    loan_period_days INT NOT NULL,
+    max_loans INT NOT NULL,
+    max_renewals INT NOT NULL DEFAULT 0,
+    daily_fine DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    fine_cap DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    grace_days INT NOT NULL DEFAULT 0,
+    updated_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_role_category (user_role, category),
+    FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
+
+INSERT INTO circulation_policies
+    (user_role, category, loan_period_days, max_loans, max_renewals, daily_fine, fine_cap, grace_days)
+VALUES
+    ('member', '', 14, 5, 2, 0.25, 10.00, 1),
+    ('staff', '', 28, 10, 3, 0.00, 0.00, 0),
+    ('admin', '', 28, 10, 3, 0.00, 0.00, 0);
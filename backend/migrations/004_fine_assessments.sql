@@ -0,0 +1,29 @@
+-- Dates on which the library is closed; no fines accrue on these days
+CREATE TABLE library_closed_days (
+    closed_date DATE PRIMARY KEY,
+    reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+-- Line items recording every fine calculation and the inputs used
+CREATE TABLE fine_assessments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    borrowing_id INT NOT NULL,
+    user_id INT NOT NULL,
+    policy_id INT,
+    due_date TIMESTAMP NOT NULL,
+    assessed_until TIMESTAMP NOT NULL,
+    days_late INT NOT NULL,
+    closed_days INT NOT NULL,
+    grace_days INT NOT NULL,
+    chargeable_days INT NOT NULL,
+    daily_rate DECIMAL(10, 2) NOT NULL,
+    fine_cap DECIMAL(10, 2) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_borrowing_id (borrowing_id),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (borrowing_id) REFERENCES borrowings(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (policy_id) REFERENCES circulation_policies(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
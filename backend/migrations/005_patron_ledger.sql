@@ -0,0 +1,30 @@
+-- Patron account ledger: fines charged and the payments and waivers against them
+CREATE TABLE patron_ledger_entries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    borrowing_id INT,
+    entry_type ENUM('charge', 'payment', 'waiver') NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    description VARCHAR(255),
+    payment_method VARCHAR(50),
+    receipt_number VARCHAR(32),
+    staff_id INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_receipt_number (receipt_number),
+    INDEX idx_user_id (user_id),
+    INDEX idx_borrowing_id (borrowing_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (borrowing_id) REFERENCES borrowings(id) ON DELETE SET NULL,
+    FOREIGN KEY (staff_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
+
+-- Carry over fines recorded before the ledger existed
+INSERT INTO patron_ledger_entries (user_id, borrowing_id, entry_type, amount, description, created_at)
+SELECT user_id, id, 'charge', fine_amount, 'Overdue fine', COALESCE(returned_date, updated_at)
+FROM borrowings
+WHERE fine_amount > 0;
+
+INSERT INTO patron_ledger_entries (user_id, borrowing_id, entry_type, amount, description, created_at)
+SELECT user_id, id, 'payment', fine_amount, 'Paid before ledger migration', updated_at
+FROM borrowings
+WHERE fine_amount > 0 AND fine_paid = TRUE;
@@ -0,0 +1,12 @@
+-- Renewal history; the number of rows per borrowing is its renewal count
+CREATE TABLE loan_renewals (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    borrowing_id INT NOT NULL,
+    previous_due_date TIMESTAMP NOT NULL,
+    new_due_date TIMESTAMP NOT NULL,
+    renewed_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_borrowing_id (borrowing_id),
+    FOREIGN KEY (borrowing_id) REFERENCES borrowings(id) ON DELETE CASCADE,
+    FOREIGN KEY (renewed_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
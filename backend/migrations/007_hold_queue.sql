@@ -0,0 +1,9 @@
+-- Holds that are ready for pickup have a copy set aside until the pickup deadline
+ALTER TABLE reservations
+    MODIFY COLUMN status ENUM('pending', 'ready', 'fulfilled', 'cancelled', 'expired') DEFAULT 'pending',
+    ADD COLUMN book_copy_id INT NULL AFTER book_id,
+    ADD COLUMN ready_date TIMESTAMP NULL AFTER status,
+    ADD COLUMN pickup_expires_at TIMESTAMP NULL AFTER ready_date,
+    ADD INDEX idx_book_queue (book_id, status, reservation_date),
+    ADD INDEX idx_book_copy_id (book_copy_id),
+    ADD FOREIGN KEY (book_copy_id) REFERENCES book_copies(id) ON DELETE SET NULL;
@@ -0,0 +1,24 @@
+-- Domain events written in the same transaction as the change they describe
+CREATE TABLE outbox_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id INT NOT NULL,
+    payload JSON NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_pending (dispatched_at, next_attempt_at),
+    INDEX idx_aggregate (aggregate_type, aggregate_id)
+) ENGINE=InnoDB;
+
+-- Subscribers that already handled an event, so retries skip them
+CREATE TABLE outbox_deliveries (
+    event_id BIGINT NOT NULL,
+    subscriber VARCHAR(100) NOT NULL,
+    delivered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, subscriber),
+    FOREIGN KEY (event_id) REFERENCES outbox_events(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
@@ -0,0 +1,14 @@
+-- Last TOTP time step accepted per user, so a code cannot be replayed
+ALTER TABLE users
+    ADD COLUMN two_factor_last_step BIGINT NULL AFTER two_factor_enabled;
+
+-- One-time recovery codes for users who lose their authenticator; only hashes are stored
+CREATE TABLE two_factor_recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_recovery_code (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
@@ -0,0 +1,19 @@
+-- A session groups the tokens issued by one login, so users can see where they
+-- are signed in and revoke a device without signing out everywhere
+CREATE TABLE user_sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    device VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+ALTER TABLE access_tokens
+    ADD COLUMN session_id INT NULL AFTER user_id,
+    ADD INDEX idx_session_id (session_id),
+    ADD CONSTRAINT fk_access_tokens_session FOREIGN KEY (session_id) REFERENCES user_sessions(id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- Rotated refresh tokens are kept, so presenting one again can be recognised as
+-- reuse of a stolen token. Each session is one refresh token family.
+ALTER TABLE access_tokens
+    ADD COLUMN rotated_at TIMESTAMP NULL AFTER revoked;
+
+ALTER TABLE user_sessions
+    ADD COLUMN revoked_reason VARCHAR(50) NULL AFTER revoked_at;
+
+ALTER TABLE auth_logs
+    ADD COLUMN severity VARCHAR(20) NOT NULL DEFAULT 'info' AFTER status,
+    ADD INDEX idx_severity (severity);
@@ -0,0 +1,4 @@
+-- Accounts lock for a growing period after repeated failed logins
+ALTER TABLE users
+    ADD COLUMN locked_until TIMESTAMP NULL AFTER failed_login_attempts,
+    ADD COLUMN lockout_count INT NOT NULL DEFAULT 0 AFTER locked_until;
@@ -0,0 +1,12 @@
+-- Asymmetric JWT signing keys. The newest unretired key signs new tokens;
+-- retired keys keep verifying until every token they signed has expired.
+CREATE TABLE signing_keys (
+    id VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL,
+    private_key TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB;
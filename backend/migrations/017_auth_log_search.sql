@@ -0,0 +1,5 @@
+-- Indexes for searching the auth log and scanning it for anomalies
+ALTER TABLE auth_logs
+    ADD INDEX idx_user_created (user_id, created_at),
+    ADD INDEX idx_ip_created (ip_address, created_at),
+    ADD INDEX idx_action_status_created (action, status, created_at);
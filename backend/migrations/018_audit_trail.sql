@@ -0,0 +1,7 @@
+-- Activity logs become the audit trail for catalog, circulation and user
+-- changes: each entry records the request that made it and a field diff.
+ALTER TABLE activity_logs
+    ADD COLUMN request_id VARCHAR(64) NULL AFTER ip_address,
+    ADD COLUMN changes JSON NULL AFTER new_value,
+    ADD INDEX idx_entity_history (entity_type, entity_id, created_at),
+    ADD INDEX idx_request_id (request_id);
@@ -0,0 +1,5 @@
+-- Closed accounts keep their row, so loans and fines still count towards
+-- circulation statistics, but their personal fields are anonymized
+ALTER TABLE users
+    ADD COLUMN closed_at TIMESTAMP NULL AFTER account_status,
+    ADD INDEX idx_closed_at (closed_at);
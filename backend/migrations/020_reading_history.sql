@@ -0,0 +1,13 @@
+-- Patrons choose whether their returned loans are kept; NULL follows the
+-- library default
+ALTER TABLE users
+    ADD COLUMN keep_reading_history BOOLEAN NULL AFTER closed_at;
+
+-- Returned loans of patrons who do not keep their history lose the link to
+-- the patron, but stay counted in circulation statistics
+ALTER TABLE borrowings
+    MODIFY user_id INT NULL,
+    ADD INDEX idx_returned_date (returned_date);
+
+ALTER TABLE fine_assessments
+    MODIFY user_id INT NULL;
@@ -0,0 +1,22 @@
+-- Self-registered accounts start out pending until their email is verified
+-- and, when the library requires it, staff approve them
+ALTER TABLE users
+    MODIFY account_status ENUM('pending', 'active', 'suspended', 'deactivated') NOT NULL DEFAULT 'active',
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email,
+    ADD INDEX idx_account_status (account_status);
+
+-- Accounts created before registration existed were set up by staff
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Single use email verification links; only a hash of the token is stored
+CREATE TABLE email_verification_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_token_hash (token_hash),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
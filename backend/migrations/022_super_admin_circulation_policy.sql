@@ -0,0 +1,6 @@
+-- Super admins borrow under the same default rules as admins
+INSERT INTO circulation_policies
+    (user_role, category, loan_period_days, max_loans, max_renewals, daily_fine, fine_cap, grace_days)
+VALUES
+    ('super_admin', '', 28, 10, 3, 0.00, 0.00, 0)
+ON DUPLICATE KEY UPDATE user_role = user_role;
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Account closure errors
+var (
+	ErrAccountHasOpenLoans = errors.New("account has loans that are not returned")
+	ErrAccountHasBalance   = errors.New("account has an outstanding balance")
+	ErrAccountClosed       = errors.New("account is already closed")
+)
+
+// UserDataExport is everything the library holds about a patron, in a
+// machine readable form they can take with them
+type UserDataExport struct {
+	ExportedAt      time.Time         `json:"exported_at"`
+	Profile         *User             `json:"profile"`
+	Loans           []*Borrowing      `json:"loans"`
+	FineAssessments []*FineAssessment `json:"fine_assessments"`
+	AccountEntries  []*LedgerEntry    `json:"account_entries"`
+	Holds           []*Reservation    `json:"holds"`
+	AuthLogs        []*AuthLog        `json:"auth_logs"`
+}
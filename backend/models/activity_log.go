@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrUnknownEntityType is returned when history is requested for an entity
+// type that is not audited
+var ErrUnknownEntityType = errors.New("unknown entity type")
+
+// Audited entity types
+const (
+	EntityBook        = "book"
+	EntityBookCopy    = "book_copy"
+	EntityBorrowing   = "borrowing"
+	EntityFine        = "fine"
+	EntityLedgerEntry = "ledger_entry"
+	EntityUser        = "user"
+)
+
+// Audit trail actions
+const (
+	ActivityCreate = "create"
+	ActivityUpdate = "update"
+	ActivityDelete = "delete"
+	ActivityClose  = "close"
+	ActivityReject = "reject"
+)
+
+// IsAuditedEntity reports whether changes to an entity type are audited
+func IsAuditedEntity(entityType string) bool {
+	switch entityType {
+	case EntityBook, EntityBookCopy, EntityBorrowing, EntityFine, EntityLedgerEntry, EntityUser:
+		return true
+	}
+	return false
+}
+
+// ActivityLog is an audit record of a change made by a user
+type ActivityLog struct {
+	ID         int64           `json:"id"`
+	UserID     *int64          `json:"user_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	OldValue   string          `json:"old_value,omitempty"`
+	NewValue   string          `json:"new_value,omitempty"`
+	Changes    json.RawMessage `json:"changes,omitempty"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// FieldChange is the old and new value of one field in an audit diff
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Actor is who made a change and the request that made it. Background jobs
+// act with a zero UserID.
+type Actor struct {
+	UserID    int64
+	IPAddress string
+	RequestID string
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Auth log search errors
+var (
+	ErrInvalidAuthLogFilter = errors.New("invalid auth log filter")
+	ErrInvalidExportFormat  = errors.New("export format must be csv or ndjson")
+)
+
+// Auth log export formats
+const (
+	AuthLogExportCSV    = "csv"
+	AuthLogExportNDJSON = "ndjson"
+)
+
+// Auth log anomaly types
+const (
+	AuthAnomalyFailureBurst = "failed_login_burst"
+	AuthAnomalyNewUserAgent = "new_user_agent"
+)
+
+// AuthLogFilters narrows an auth log search. Zero values match everything;
+// From is inclusive and To exclusive.
+type AuthLogFilters struct {
+	UserID    int64
+	Action    string
+	Status    string
+	IPAddress string
+	From      time.Time
+	To        time.Time
+}
+
+// AuthLogAnomaly is a suspicious pattern found in the auth logs. Failure
+// bursts are reported per address; new user agents per successful login.
+type AuthLogAnomaly struct {
+	Type      string    `json:"type"`
+	UserID    *int64    `json:"user_id,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Count     int       `json:"count"`
+	Users     int       `json:"users,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
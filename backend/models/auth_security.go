@@ -0,0 +1,18 @@
+package models
+
+// AuthActionRefreshTokenReuse is logged when a rotated refresh token is
+// presented again
+const AuthActionRefreshTokenReuse = "refresh_token_reuse"
+
+// Auth log severities
+const (
+	AuthSeverityInfo = "info"
+	AuthSeverityHigh = "high"
+)
+
+// Reasons a session was revoked
+const (
+	SessionRevokedLogout     = "logout"
+	SessionRevokedByUser     = "revoked"
+	SessionRevokedTokenReuse = "refresh_token_reuse"
+)
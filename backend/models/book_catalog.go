@@ -0,0 +1,21 @@
+package models
+
+import "errors"
+
+// Catalog errors
+var (
+	ErrInvalidBook        = errors.New("book title, author and ISBN are required")
+	ErrDuplicateISBN      = errors.New("a book with this ISBN already exists")
+	ErrBookHasActiveLoans = errors.New("book has copies that are currently on loan")
+)
+
+// BookFilters represents the filters available when listing books
+type BookFilters struct {
+	Search    string // matches title, author or ISBN
+	Category  string
+	Language  string
+	Author    string
+	YearFrom  int
+	YearTo    int
+	Available *bool // nil lists all books, true only books with available copies
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// BookCopyStatusWithdrawn marks a copy that was deliberately removed from the collection
+const BookCopyStatusWithdrawn = "withdrawn"
+
+// Book copy errors
+var (
+	ErrBookCopyNotFound  = errors.New("book copy not found")
+	ErrDuplicateBarcode  = errors.New("a copy with this barcode already exists")
+	ErrInvalidBarcode    = errors.New("barcode is required")
+	ErrInvalidCopyStatus = errors.New("invalid book copy status")
+	ErrBookCopyOnLoan    = errors.New("book copy is currently on loan")
+	ErrBookCopyRetired   = errors.New("book copy has been retired")
+)
+
+// BookCopy represents a single physical item of a book
+type BookCopy struct {
+	ID              int64      `json:"id"`
+	BookID          int64      `json:"book_id"`
+	CopyNumber      string     `json:"copy_number"`
+	Barcode         string     `json:"barcode"`
+	Status          string     `json:"status"`
+	Location        string     `json:"location"`
+	Condition       string     `json:"condition"`
+	AcquisitionDate *time.Time `json:"acquisition_date,omitempty"`
+	RetiredAt       *time.Time `json:"retired_at,omitempty"`
+	Notes           string     `json:"notes,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IsRetiredCopyStatus reports whether status takes a copy out of the collection
+func IsRetiredCopyStatus(status string) bool {
+	switch status {
+	case BookCopyStatusLost, BookCopyStatusDamaged, BookCopyStatusWithdrawn:
+		return true
+	}
+	return false
+}
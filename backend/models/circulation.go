@@ -0,0 +1,12 @@
+package models
+
+import "errors"
+
+// Circulation errors
+var (
+	ErrAccountSuspended         = errors.New("account is suspended")
+	ErrLoanLimitReached         = errors.New("maximum number of concurrent loans reached")
+	ErrUnpaidFines              = errors.New("account has unpaid fines")
+	ErrBookCopyNotAvailable     = errors.New("book copy is not available for checkout")
+	ErrBorrowingAlreadyReturned = errors.New("borrowing has already been returned")
+)
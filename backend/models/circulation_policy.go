@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Circulation policy errors
+var (
+	ErrPolicyNotFound        = errors.New("no circulation policy applies")
+	ErrInvalidPolicy         = errors.New("invalid circulation policy")
+	ErrDefaultPolicyRequired = errors.New("the default policy for a role cannot be deleted")
+)
+
+// CirculationPolicy holds the lending rules for a user role and book category.
+// An empty Category is the default for the role.
+type CirculationPolicy struct {
+	ID             int64     `json:"id"`
+	UserRole       UserRole  `json:"user_role"`
+	Category       string    `json:"category"`
+	LoanPeriodDays int       `json:"loan_period_days"`
+	MaxLoans       int       `json:"max_loans"`
+	MaxRenewals    int       `json:"max_renewals"`
+	DailyFine      float64   `json:"daily_fine"`
+	FineCap        float64   `json:"fine_cap"`
+	GraceDays      int       `json:"grace_days"`
+	UpdatedBy      *int64    `json:"updated_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate checks that the policy values are usable
+func (p *CirculationPolicy) Validate() error {
+	if !IsValidRole(p.UserRole) || p.LoanPeriodDays < 1 || p.MaxLoans < 0 ||
+		p.MaxRenewals < 0 || p.DailyFine < 0 || p.FineCap < 0 || p.GraceDays < 0 {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
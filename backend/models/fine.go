@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Fine errors
+var (
+	ErrInvalidClosedDay = errors.New("invalid library closed day")
+)
+
+// FineAssessment is an auditable record of one fine calculation for a borrowing
+type FineAssessment struct {
+	ID             int64     `json:"id"`
+	BorrowingID    int64     `json:"borrowing_id"`
+	UserID         int64     `json:"user_id"`
+	PolicyID       int64     `json:"policy_id"`
+	DueDate        time.Time `json:"due_date"`
+	AssessedUntil  time.Time `json:"assessed_until"`
+	DaysLate       int       `json:"days_late"`
+	ClosedDays     int       `json:"closed_days"`
+	GraceDays      int       `json:"grace_days"`
+	ChargeableDays int       `json:"chargeable_days"`
+	DailyRate      float64   `json:"daily_rate"`
+	FineCap        float64   `json:"fine_cap"`
+	Amount         float64   `json:"amount"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ClosedDay is a date on which the library is closed and no fines accrue
+type ClosedDay struct {
+	Date      time.Time `json:"date"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Single sign-on errors
+var (
+	ErrSSODisabled         = errors.New("single sign-on is not enabled")
+	ErrInvalidSSOState     = errors.New("sign-in request expired or is unknown, please start again")
+	ErrSSOLoginFailed      = errors.New("sign-in with the identity provider failed")
+	ErrSSOEmailNotVerified = errors.New("the identity provider has not verified this email address")
+)
+
+// UserIdentity links a local account to a subject at an identity provider
+type UserIdentity struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Issuer      string     `json:"issuer"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// SSOLoginState is a pending authorization request
+type SSOLoginState struct {
+	State        string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
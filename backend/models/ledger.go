@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Ledger entry types
+const (
+	LedgerEntryCharge  = "charge"
+	LedgerEntryPayment = "payment"
+	LedgerEntryWaiver  = "waiver"
+)
+
+// Patron account errors
+var (
+	ErrInvalidAmount        = errors.New("amount must be greater than zero")
+	ErrAmountExceedsBalance = errors.New("amount exceeds the outstanding balance")
+	ErrReceiptNotFound      = errors.New("receipt not found")
+)
+
+// LedgerEntry is a single charge, payment or waiver on a patron's account.
+// Amounts are always positive; the entry type decides the direction.
+type LedgerEntry struct {
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"user_id"`
+	BorrowingID   *int64    `json:"borrowing_id,omitempty"`
+	EntryType     string    `json:"entry_type"`
+	Amount        float64   `json:"amount"`
+	Description   string    `json:"description"`
+	PaymentMethod string    `json:"payment_method,omitempty"`
+	ReceiptNumber string    `json:"receipt_number,omitempty"`
+	StaffID       *int64    `json:"staff_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AccountBalance summarizes a patron's account
+type AccountBalance struct {
+	UserID   int64   `json:"user_id"`
+	Charges  float64 `json:"charges"`
+	Payments float64 `json:"payments"`
+	Waivers  float64 `json:"waivers"`
+	Balance  float64 `json:"balance"`
+}
+
+// Receipt is issued to the patron for every payment
+type Receipt struct {
+	Number        string    `json:"number"`
+	UserID        int64     `json:"user_id"`
+	UserName      string    `json:"user_name"`
+	BorrowingID   *int64    `json:"borrowing_id,omitempty"`
+	Amount        float64   `json:"amount"`
+	PaymentMethod string    `json:"payment_method"`
+	StaffID       *int64    `json:"staff_id,omitempty"`
+	IssuedAt      time.Time `json:"issued_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Lockout errors
+var (
+	ErrAccountLocked        = errors.New("account is temporarily locked after too many failed logins")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts from this address, try again later")
+)
+
+// Auth log actions for account lockouts
+const (
+	AuthActionAccountLocked   = "account_locked"
+	AuthActionAccountUnlocked = "account_unlocked"
+)
+
+// LockoutStatus is a user's failed login state. LockoutCount is how many
+// times the account locked since the last successful login; each lockout
+// lasts longer than the one before.
+type LockoutStatus struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	LockoutCount   int        `json:"lockout_count"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
+
+// IsLocked reports whether the account is locked at the given time
+func (s *LockoutStatus) IsLocked(now time.Time) bool {
+	return s.LockedUntil != nil && s.LockedUntil.After(now)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Notification types
+const (
+	NotificationDueSoon          = "due_soon"
+	NotificationOverdue          = "overdue"
+	NotificationHoldReady        = "hold_ready"
+	NotificationPasswordReset    = "password_reset"
+	NotificationTwoFactorChanged = "two_factor_changed"
+	NotificationSessionRevoked   = "session_revoked"
+	NotificationVerifyEmail      = "verify_email"
+	NotificationAccountApproved  = "account_approved"
+)
+
+// Notification errors
+var (
+	ErrUnknownNotificationType = errors.New("unknown notification type")
+	ErrNotificationRequired    = errors.New("security notifications cannot be turned off")
+)
+
+// NotificationTypes lists every notification a patron can receive
+var NotificationTypes = []string{
+	NotificationDueSoon,
+	NotificationOverdue,
+	NotificationHoldReady,
+	NotificationPasswordReset,
+	NotificationTwoFactorChanged,
+	NotificationSessionRevoked,
+	NotificationVerifyEmail,
+	NotificationAccountApproved,
+}
+
+// IsNotificationType reports whether t is a known notification type
+func IsNotificationType(t string) bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMandatoryNotification reports whether a notification is always sent.
+// Account security and registration messages ignore the patron's preferences.
+func IsMandatoryNotification(t string) bool {
+	switch t {
+	case NotificationPasswordReset, NotificationTwoFactorChanged, NotificationSessionRevoked,
+		NotificationVerifyEmail, NotificationAccountApproved:
+		return true
+	}
+	return false
+}
+
+// NotificationMessage is a rendered message ready to be sent on a channel
+type NotificationMessage struct {
+	Type    string
+	To      string
+	Name    string
+	Subject string
+	Body    string
+}
+
+// NotificationPreference records whether a patron wants a type of notification
+type NotificationPreference struct {
+	Type      string     `json:"type"`
+	Enabled   bool       `json:"enabled"`
+	Mandatory bool       `json:"mandatory"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
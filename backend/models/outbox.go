@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types
+const (
+	EventLoanCreated       = "loan.created"
+	EventLoanReturned      = "loan.returned"
+	EventLoanOverdue       = "loan.overdue"
+	EventCopyStatusChanged = "copy.status_changed"
+	EventUserSuspended     = "user.suspended"
+)
+
+// EventTypes lists every domain event type
+var EventTypes = []string{
+	EventLoanCreated,
+	EventLoanReturned,
+	EventLoanOverdue,
+	EventCopyStatusChanged,
+	EventUserSuspended,
+}
+
+// IsEventType reports whether t is a known domain event type
+func IsEventType(t string) bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Aggregate types that domain events are about
+const (
+	AggregateBorrowing = "borrowing"
+	AggregateBookCopy  = "book_copy"
+	AggregateUser      = "user"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the
+// change it describes and delivered to subscribers afterwards
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,80 @@
+package models
+
+// Permission is an action a role may perform
+type Permission string
+
+// Permissions
+const (
+	PermBooksWrite     Permission = "books:write"
+	PermLoansCheckout  Permission = "loans:checkout"
+	PermLoansManage    Permission = "loans:manage"
+	PermHoldsManage    Permission = "holds:manage"
+	PermAccountsRead   Permission = "accounts:read"
+	PermPaymentsRecord Permission = "payments:record"
+	PermFinesWaive     Permission = "fines:waive"
+	PermUsersManage    Permission = "users:manage"
+	PermAdminsManage   Permission = "admins:manage"
+	PermPoliciesManage Permission = "policies:manage"
+	PermWebhooksManage Permission = "webhooks:manage"
+	PermAuditLogsRead  Permission = "audit_logs:read"
+)
+
+// staffPermissions are granted to every library staff role
+var staffPermissions = []Permission{
+	PermBooksWrite,
+	PermLoansCheckout,
+	PermLoansManage,
+	PermHoldsManage,
+	PermAccountsRead,
+	PermPaymentsRecord,
+	PermFinesWaive,
+}
+
+// adminPermissions are granted to admins on top of the staff permissions
+var adminPermissions = []Permission{
+	PermUsersManage,
+	PermPoliciesManage,
+	PermWebhooksManage,
+	PermAuditLogsRead,
+}
+
+// rolePermissions maps each role to the permissions it grants. Members have
+// none; they may only act on their own loans, holds and account.
+var rolePermissions = map[UserRole]map[Permission]bool{
+	UserRoleMember:     permissionSet(),
+	UserRoleStaff:      permissionSet(staffPermissions),
+	UserRoleAdmin:      permissionSet(staffPermissions, adminPermissions),
+	UserRoleSuperAdmin: permissionSet(staffPermissions, adminPermissions, []Permission{PermAdminsManage}),
+}
+
+// IsValidRole reports whether role is one of the defined user roles
+func IsValidRole(role UserRole) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// permissionSet merges permission lists into a lookup set
+func permissionSet(lists ...[]Permission) map[Permission]bool {
+	set := make(map[Permission]bool)
+	for _, list := range lists {
+		for _, permission := range list {
+			set[permission] = true
+		}
+	}
+	return set
+}
+
+// HasPermission reports whether a role grants a permission
+func HasPermission(role UserRole, permission Permission) bool {
+	return rolePermissions[role][permission]
+}
+
+// HasAllPermissions reports whether a role grants every given permission
+func HasAllPermissions(role UserRole, permissions ...Permission) bool {
+	for _, permission := range permissions {
+		if !HasPermission(role, permission) {
+			return false
+		}
+	}
+	return true
+}
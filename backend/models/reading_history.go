@@ -0,0 +1,11 @@
+package models
+
+// ReadingHistoryPreference is whether a patron's returned loans are kept.
+// Loans of patrons who do not keep them are unlinked from the patron once
+// RetentionDays have passed since their return.
+type ReadingHistoryPreference struct {
+	UserID         int64 `json:"user_id"`
+	KeepHistory    bool  `json:"keep_history"`
+	LibraryDefault bool  `json:"library_default"`
+	RetentionDays  int   `json:"retention_days"`
+}
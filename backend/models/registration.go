@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// UserStatusPending is the status of a self-registered account until its
+// email is verified and, when required, staff approve it
+const UserStatusPending UserStatus = "pending"
+
+// Registration errors
+var (
+	ErrRegistrationDisabled     = errors.New("self-registration is disabled")
+	ErrInvalidRegistration      = errors.New("email, full name and a password of at least 8 characters are required")
+	ErrEmailTaken               = errors.New("an account with this email already exists")
+	ErrPhoneTaken               = errors.New("an account with this phone number already exists")
+	ErrInvalidPhone             = errors.New("invalid phone number")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrRegistrationNotPending   = errors.New("account is not awaiting approval")
+	ErrEmailNotVerified         = errors.New("email address has not been verified")
+)
+
+// Auth log actions for registration
+const (
+	AuthActionRegister      = "register"
+	AuthActionEmailVerified = "email_verified"
+)
+
+// Registration is the information a patron signs up with
+type Registration struct {
+	Email    string
+	Password string
+	FullName string
+	Phone    string
+	Address  string
+}
+
+// RegistrationStatus tells a patron where their registration stands
+type RegistrationStatus struct {
+	UserID           int64      `json:"user_id"`
+	Email            string     `json:"email"`
+	AccountStatus    UserStatus `json:"account_status"`
+	EmailVerified    bool       `json:"email_verified"`
+	AwaitingApproval bool       `json:"awaiting_approval"`
+}
+
+// PendingRegistration is a verified account waiting in the staff approval queue
+type PendingRegistration struct {
+	ID              int64     `json:"id"`
+	Email           string    `json:"email"`
+	FullName        string    `json:"full_name"`
+	Phone           string    `json:"phone,omitempty"`
+	Address         string    `json:"address,omitempty"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
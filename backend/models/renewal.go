@@ -0,0 +1,24 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Renewal errors
+var (
+	ErrMaxRenewalsReached = errors.New("maximum number of renewals reached")
+	ErrTitleOnHold        = errors.New("another patron is waiting for this title")
+	ErrLoanOverdue        = errors.New("overdue loans cannot be renewed")
+	ErrCannotRenew        = errors.New("borrowing cannot be renewed")
+)
+
+// LoanRenewal records one extension of a borrowing's due date
+type LoanRenewal struct {
+	ID              int64     `json:"id"`
+	BorrowingID     int64     `json:"borrowing_id"`
+	PreviousDueDate time.Time `json:"previous_due_date"`
+	NewDueDate      time.Time `json:"new_due_date"`
+	RenewedBy       *int64    `json:"renewed_by,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Reservation statuses
+const (
+	ReservationStatusPending   = "pending"
+	ReservationStatusReady     = "ready"
+	ReservationStatusFulfilled = "fulfilled"
+	ReservationStatusCancelled = "cancelled"
+	ReservationStatusExpired   = "expired"
+)
+
+// Reservation errors
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrAlreadyOnHold       = errors.New("you already have a hold on this title")
+	ErrCopiesAvailable     = errors.New("copies of this title are available to borrow")
+	ErrReservationClosed   = errors.New("reservation is no longer active")
+)
+
+// Reservation is a patron's place in the hold queue for a book
+type Reservation struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"user_id"`
+	BookID          int64      `json:"book_id"`
+	BookCopyID      *int64     `json:"book_copy_id,omitempty"`
+	Status          string     `json:"status"`
+	ReservationDate time.Time  `json:"reservation_date"`
+	ExpiryDate      time.Time  `json:"expiry_date"`
+	ReadyDate       *time.Time `json:"ready_date,omitempty"`
+	PickupExpiresAt *time.Time `json:"pickup_expires_at,omitempty"`
+	FulfilledDate   *time.Time `json:"fulfilled_date,omitempty"`
+	Position        int        `json:"position,omitempty"`
+	BookTitle       string     `json:"book_title,omitempty"`
+	UserName        string     `json:"user_name,omitempty"`
+}
+
+// IsOpen reports whether the reservation is still waiting or waiting for pickup
+func (r *Reservation) IsOpen() bool {
+	return r.Status == ReservationStatusPending || r.Status == ReservationStatusReady
+}
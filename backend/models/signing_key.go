@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SigningKey is a key pair used to sign JWTs. The private key is stored
+// encrypted; the public key is a PEM encoded PKIX block.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey string
+	PublicKey  string
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+	ExpiresAt  *time.Time
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	// RSA keys
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+
+	// Elliptic curve and Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKS is the key set published for services that verify our tokens
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
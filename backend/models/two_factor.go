@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Two-factor authentication errors
+var (
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment has not been started")
+)
+
+// TwoFactorEnrollment is returned when a user starts setting up an
+// authenticator app. The secret stays inactive until a code is confirmed.
+type TwoFactorEnrollment struct {
+	// Secret is the base32 key for manual entry in the authenticator app
+	Secret string `json:"secret"`
+
+	// OTPAuthURI is the otpauth:// key URI understood by authenticator apps
+	OTPAuthURI string `json:"otpauth_uri"`
+
+	// QRPayload is the text clients render as a QR code for scanning
+	QRPayload string `json:"qr_payload"`
+}
+
+// LoginResult is the outcome of a password login. Users with two-factor
+// authentication get an MFA token to exchange for tokens with a code.
+type LoginResult struct {
+	User         *User      `json:"user"`
+	Tokens       *TokenPair `json:"tokens,omitempty"`
+	MFARequired  bool       `json:"mfa_required"`
+	MFAToken     string     `json:"mfa_token,omitempty"`
+	MFAExpiresAt *time.Time `json:"mfa_expires_at,omitempty"`
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"library-management-system/internal/models"
+)
+
+// ActivityLogRepository handles database operations for activity logs
+type ActivityLogRepository struct {
+	db *Database
+}
+
+// NewActivityLogRepository creates a new ActivityLogRepository instance
+func NewActivityLogRepository(db *Database) *ActivityLogRepository {
+	return &ActivityLogRepository{db: db}
+}
+
+// Create adds an activity log entry
+func (r *ActivityLogRepository) Create(tx *sql.Tx, log *models.ActivityLog) error {
+	query := `
+		INSERT INTO activity_logs (
+			user_id, action, entity_type, entity_id, old_value, new_value, changes,
+			ip_address, request_id
+		) VALUES (?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), NULLIF(?, ''))`
+
+	var changes interface{}
+	if len(log.Changes) > 0 {
+		changes = string(log.Changes)
+	}
+
+	args := []interface{}{
+		log.UserID, log.Action, log.EntityType, log.EntityID,
+		log.OldValue, log.NewValue, changes, log.IPAddress, log.RequestID,
+	}
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	log.ID = id
+	return nil
+}
+
+// ListByEntity retrieves the change history of an entity, newest first
+func (r *ActivityLogRepository) ListByEntity(entityType string, entityID int64, page, pageSize int) ([]*models.ActivityLog, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT id, user_id, action, entity_type, entity_id, old_value, new_value,
+			changes, ip_address, request_id, created_at
+		FROM activity_logs
+		WHERE entity_type = ? AND entity_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, entityType, entityID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.ActivityLog
+	for rows.Next() {
+		var log models.ActivityLog
+		var userID sql.NullInt64
+		var oldValue, newValue, changes, ipAddress, requestID sql.NullString
+
+		err := rows.Scan(
+			&log.ID, &userID, &log.Action, &log.EntityType, &log.EntityID,
+			&oldValue, &newValue, &changes, &ipAddress, &requestID, &log.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if userID.Valid {
+			log.UserID = &userID.Int64
+		}
+		if changes.Valid {
+			log.Changes = json.RawMessage(changes.String)
+		}
+		log.OldValue = oldValue.String
+		log.NewValue = newValue.String
+		log.IPAddress = ipAddress.String
+		log.RequestID = requestID.String
+
+		logs = append(logs, &log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// CountByEntity counts the history entries of an entity
+func (r *ActivityLogRepository) CountByEntity(entityType string, entityID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM activity_logs WHERE entity_type = ? AND entity_id = ?`
+
+	var count int
+	err := r.db.QueryRow(query, entityType, entityID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// authLogColumns are the columns scanned by scanAuthLog
+const authLogColumns = `
+	id, user_id, action, status, severity, ip_address, user_agent, details, created_at`
+
+// Search retrieves the auth logs matching the filters, newest first
+func (r *AuthLogRepository) Search(filters models.AuthLogFilters, page, pageSize int) ([]*models.AuthLog, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	where, args := authLogConditions(filters)
+	query := `SELECT ` + authLogColumns + ` FROM auth_logs WHERE 1=1` + where +
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, pageSize, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.AuthLog
+	for rows.Next() {
+		log, err := scanAuthLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// CountMatching returns the number of auth logs matching the filters
+func (r *AuthLogRepository) CountMatching(filters models.AuthLogFilters) (int, error) {
+	where, args := authLogConditions(filters)
+
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM auth_logs WHERE 1=1`+where, args...).Scan(&count)
+	return count, err
+}
+
+// EachMatching calls fn for up to limit auth logs matching the filters,
+// oldest first, without holding them all in memory
+func (r *AuthLogRepository) EachMatching(filters models.AuthLogFilters, limit int, fn func(*models.AuthLog) error) error {
+	where, args := authLogConditions(filters)
+	query := `SELECT ` + authLogColumns + ` FROM auth_logs WHERE 1=1` + where +
+		` ORDER BY created_at, id LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanAuthLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// LoginFailureBursts finds addresses with at least threshold failed logins
+// since the given time, most failures first
+func (r *AuthLogRepository) LoginFailureBursts(since time.Time, threshold int) ([]*models.AuthLogAnomaly, error) {
+	query := `
+		SELECT ip_address, COUNT(*), COUNT(DISTINCT user_id), MIN(created_at), MAX(created_at)
+		FROM auth_logs
+		WHERE action = ? AND status = ? AND created_at >= ? AND ip_address IS NOT NULL
+		GROUP BY ip_address
+		HAVING COUNT(*) >= ?
+		ORDER BY COUNT(*) DESC`
+
+	rows, err := r.db.Query(query, models.AuthActionLogin, models.StatusFailure, since, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*models.AuthLogAnomaly
+	for rows.Next() {
+		anomaly := models.AuthLogAnomaly{Type: models.AuthAnomalyFailureBurst}
+		err := rows.Scan(
+			&anomaly.IPAddress, &anomaly.Count, &anomaly.Users,
+			&anomaly.FirstSeen, &anomaly.LastSeen,
+		)
+		if err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, &anomaly)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
+
+// NewUserAgentLogins finds successful logins since the given time from a
+// user agent the user had never logged in with before. A user's first
+// login is not reported.
+func (r *AuthLogRepository) NewUserAgentLogins(since time.Time) ([]*models.AuthLogAnomaly, error) {
+	query := `
+		SELECT l.user_id, l.ip_address, l.user_agent, l.created_at
+		FROM auth_logs l
+		WHERE l.action = ? AND l.status = ? AND l.created_at >= ? AND l.user_id IS NOT NULL
+			AND EXISTS (
+				SELECT 1 FROM auth_logs p
+				WHERE p.user_id = l.user_id AND p.action = l.action AND p.status = l.status
+					AND p.id < l.id
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM auth_logs p
+				WHERE p.user_id = l.user_id AND p.action = l.action AND p.status = l.status
+					AND p.user_agent <=> l.user_agent AND p.id < l.id
+			)
+		ORDER BY l.created_at DESC`
+
+	rows, err := r.db.Query(query, models.AuthActionLogin, models.StatusSuccess, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*models.AuthLogAnomaly
+	for rows.Next() {
+		anomaly := models.AuthLogAnomaly{Type: models.AuthAnomalyNewUserAgent, Count: 1}
+		var userID int64
+		var ipAddress, userAgent sql.NullString
+
+		if err := rows.Scan(&userID, &ipAddress, &userAgent, &anomaly.FirstSeen); err != nil {
+			return nil, err
+		}
+
+		anomaly.UserID = &userID
+		anomaly.IPAddress = ipAddress.String
+		anomaly.UserAgent = userAgent.String
+		anomaly.LastSeen = anomaly.FirstSeen
+		anomalies = append(anomalies, &anomaly)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
+
+// authLogConditions builds the WHERE clauses for auth log filters
+func authLogConditions(filters models.AuthLogFilters) (string, []interface{}) {
+	where := ""
+	args := []interface{}{}
+
+	if filters.UserID != 0 {
+		where += " AND user_id = ?"
+		args = append(args, filters.UserID)
+	}
+
+	if filters.Action != "" {
+		where += " AND action = ?"
+		args = append(args, filters.Action)
+	}
+
+	if filters.Status != "" {
+		where += " AND status = ?"
+		args = append(args, filters.Status)
+	}
+
+	if filters.IPAddress != "" {
+		where += " AND ip_address = ?"
+		args = append(args, filters.IPAddress)
+	}
+
+	if !filters.From.IsZero() {
+		where += " AND created_at >= ?"
+		args = append(args, filters.From)
+	}
+
+	if !filters.To.IsZero() {
+		where += " AND created_at < ?"
+		args = append(args, filters.To)
+	}
+
+	return where, args
+}
+
+// scanAuthLog reads an auth log selected with authLogColumns
+func scanAuthLog(row rowScanner) (*models.AuthLog, error) {
+	var log models.AuthLog
+	var userID sql.NullInt64
+	var ipAddress, userAgent, details sql.NullString
+
+	err := row.Scan(
+		&log.ID, &userID, &log.Action, &log.Status, &log.Severity,
+		&ipAddress, &userAgent, &details, &log.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.UserID = userID.Int64
+	log.IPAddress = ipAddress.String
+	log.UserAgent = userAgent.String
+	log.Details = details.String
+
+	return &log, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"database/sql"
+
+	"library-management-system/internal/models"
+)
+
+// CreateWithSeverity stores an auth log entry together with its severity.
+// Entries stored with Create keep the column's 'info' default.
+func (r *AuthLogRepository) CreateWithSeverity(log *models.AuthLog) error {
+	query := `
+		INSERT INTO auth_logs (user_id, action, status, severity, ip_address, user_agent, details)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	severity := log.Severity
+	if severity == "" {
+		severity = models.AuthSeverityInfo
+	}
+
+	var userID sql.NullInt64
+	if log.UserID != 0 {
+		userID = sql.NullInt64{Int64: log.UserID, Valid: true}
+	}
+
+	result, err := r.db.Exec(
+		query,
+		userID, log.Action, log.Status, severity, log.IPAddress, log.UserAgent, log.Details,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	log.ID = id
+	return nil
+}
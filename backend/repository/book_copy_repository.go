@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"library-management-system/internal/models"
+)
+
+// BookCopyRepository handles database operations for individual book copies
+type BookCopyRepository struct {
+	db *Database
+}
+
+// NewBookCopyRepository creates a new BookCopyRepository instance
+func NewBookCopyRepository(db *Database) *BookCopyRepository {
+	return &BookCopyRepository{db: db}
+}
+
+// bookCopyColumns lists the columns read by scanBookCopy
+const bookCopyColumns = `
+		id, book_id, copy_number, barcode, status, location, ` + "`condition`" + `,
+		acquisition_date, retired_at, notes, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBookCopy reads a book copy in the order of bookCopyColumns
+func scanBookCopy(row rowScanner) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	var barcode, location, condition, notes sql.NullString
+	var acquisitionDate, retiredAt sql.NullTime
+
+	err := row.Scan(
+		&bookCopy.ID, &bookCopy.BookID, &bookCopy.CopyNumber, &barcode,
+		&bookCopy.Status, &location, &condition, &acquisitionDate,
+		&retiredAt, &notes, &bookCopy.CreatedAt, &bookCopy.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrBookCopyNotFound
+		}
+		return nil, err
+	}
+
+	bookCopy.Barcode = barcode.String
+	bookCopy.Location = location.String
+	bookCopy.Condition = condition.String
+	bookCopy.Notes = notes.String
+	if acquisitionDate.Valid {
+		bookCopy.AcquisitionDate = &acquisitionDate.Time
+	}
+	if retiredAt.Valid {
+		bookCopy.RetiredAt = &retiredAt.Time
+	}
+
+	return &bookCopy, nil
+}
+
+// GetByID retrieves a book copy by ID
+func (r *BookCopyRepository) GetByID(id int64) (*models.BookCopy, error) {
+	query := `SELECT` + bookCopyColumns + `
+		FROM book_copies
+		WHERE id = ?`
+
+	return scanBookCopy(r.db.QueryRow(query, id))
+}
+
+// GetByBarcode retrieves a book copy by barcode.
+// When tx is given the row is locked until the transaction ends.
+func (r *BookCopyRepository) GetByBarcode(tx *sql.Tx, barcode string) (*models.BookCopy, error) {
+	query := `SELECT` + bookCopyColumns + `
+		FROM book_copies
+		WHERE barcode = ?`
+
+	if tx != nil {
+		return scanBookCopy(tx.QueryRow(query+" FOR UPDATE", barcode))
+	}
+	return scanBookCopy(r.db.QueryRow(query, barcode))
+}
+
+// ListByBook retrieves all copies of a book
+func (r *BookCopyRepository) ListByBook(bookID int64) ([]*models.BookCopy, error) {
+	query := `SELECT` + bookCopyColumns + `
+		FROM book_copies
+		WHERE book_id = ?
+		ORDER BY CAST(copy_number AS UNSIGNED), copy_number`
+
+	rows, err := r.db.Query(query, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var copies []*models.BookCopy
+	for rows.Next() {
+		bookCopy, err := scanBookCopy(rows)
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, bookCopy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
+
+// Create adds a new copy and refreshes the book's copy counts. It must run
+// in a transaction.
+func (r *BookCopyRepository) Create(tx *sql.Tx, bookCopy *models.BookCopy) error {
+	// Number copies sequentially within a book unless one was given
+	if bookCopy.CopyNumber == "" {
+		var next int64
+		err := tx.QueryRow(`
+			SELECT COALESCE(MAX(CAST(copy_number AS UNSIGNED)), 0) + 1
+			FROM book_copies
+			WHERE book_id = ?
+			FOR UPDATE`, bookCopy.BookID).Scan(&next)
+		if err != nil {
+			return err
+		}
+		bookCopy.CopyNumber = strconv.FormatInt(next, 10)
+	}
+
+	query := `
+		INSERT INTO book_copies (
+			book_id, copy_number, barcode, status, location, ` + "`condition`" + `,
+			acquisition_date, notes
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(
+		query,
+		bookCopy.BookID, bookCopy.CopyNumber, bookCopy.Barcode, bookCopy.Status,
+		bookCopy.Location, bookCopy.Condition, bookCopy.AcquisitionDate,
+		bookCopy.Notes,
+	)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return models.ErrDuplicateBarcode
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	bookCopy.ID = id
+
+	return r.SyncBookCounts(tx, bookCopy.BookID)
+}
+
+// UpdateLocation moves a copy to a new shelving location
+func (r *BookCopyRepository) UpdateLocation(tx *sql.Tx, id int64, location string) error {
+	query := `
+		UPDATE book_copies
+		SET location = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, location, id)
+	} else {
+		result, err = r.db.Exec(query, location, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrBookCopyNotFound
+	}
+
+	return nil
+}
+
+// Retire takes a copy out of circulation and refreshes the book's copy
+// counts. It must run in a transaction.
+func (r *BookCopyRepository) Retire(tx *sql.Tx, id int64, status, notes string) error {
+	var bookID int64
+	err := tx.QueryRow(`SELECT book_id FROM book_copies WHERE id = ? FOR UPDATE`, id).Scan(&bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrBookCopyNotFound
+		}
+		return err
+	}
+
+	query := `
+		UPDATE book_copies
+		SET status = ?, notes = ?, retired_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	if _, err := tx.Exec(query, status, notes, id); err != nil {
+		return err
+	}
+
+	err = appendEvent(tx, models.EventCopyStatusChanged, models.AggregateBookCopy, id, map[string]interface{}{
+		"book_copy_id": id,
+		"status":       status,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.SyncBookCounts(tx, bookID)
+}
+
+// SyncBookCounts recalculates a book's total and available copies from its copy rows
+func (r *BookCopyRepository) SyncBookCounts(tx *sql.Tx, bookID int64) error {
+	return syncBookCounts(r.db, tx, bookID)
+}
+
+// syncBookCounts recalculates a book's copy counts for any repository that
+// changes copy statuses
+func syncBookCounts(db *Database, tx *sql.Tx, bookID int64) error {
+	query := `
+		UPDATE books
+		SET total_copies = (
+				SELECT COUNT(*) FROM book_copies
+				WHERE book_id = ? AND status NOT IN ('lost', 'damaged', 'withdrawn')
+			),
+			available_copies = (
+				SELECT COUNT(*) FROM book_copies
+				WHERE book_id = ? AND status = 'available'
+			),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, bookID, bookID, bookID)
+	} else {
+		_, err = db.Exec(query, bookID, bookID, bookID)
+	}
+
+	return err
+}
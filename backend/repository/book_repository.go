@@ -24,7 +24,7 @@ func (r *BookRepository) GetByID(id int64) (*models.Book, error) {
 		       description, category, language, page_count, total_copies,
 		       available_copies, location, cover_image_url, created_at, updated_at
 		FROM books
-		WHERE id = ?`
+		WHERE id = ? AND deleted_at IS NULL`
 
 	var book models.Book
 	err := r.db.QueryRow(query, id).Scan(
@@ -51,7 +51,7 @@ func (r *BookRepository) GetByISBN(isbn string) (*models.Book, error) {
 			   description, category, language, page_count, total_copies,
 			   available_copies, location, cover_image_url, created_at, updated_at
 		FROM books
-		WHERE isbn = ?`
+		WHERE isbn = ? AND deleted_at IS NULL`
 
 	var book models.Book
 	err := r.db.QueryRow(query, isbn).Scan(
@@ -70,3 +70,334 @@ func (r *BookRepository) GetByISBN(isbn string) (*models.Book, error) {
 
 	return &book, nil
 }
+
+// Create adds a new book to the catalog. A new book has no copies; its
+// counts follow the copies added to it. A soft-deleted book keeps its ISBN
+// under the unique key, so adding that ISBN again restores the deleted row
+// with the new catalog data instead of failing as a duplicate.
+func (r *BookRepository) Create(tx *sql.Tx, book *models.Book) error {
+	book.TotalCopies = 0
+	book.AvailableCopies = 0
+
+	query := `
+		INSERT INTO books (
+			isbn, title, author, publisher, publication_year, description,
+			category, language, page_count, total_copies, available_copies,
+			location, cover_image_url
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	args := []interface{}{
+		book.ISBN, book.Title, book.Author, book.Publisher, book.PublicationYear,
+		book.Description, book.Category, book.Language, book.PageCount,
+		book.TotalCopies, book.AvailableCopies, book.Location, book.CoverImageURL,
+	}
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return r.restoreDeleted(tx, book)
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	book.ID = id
+	return nil
+}
+
+// restoreDeleted brings back the soft-deleted book holding book.ISBN with
+// the new catalog data. Its copies come back with it, so the counts are
+// recalculated from the copy rows. Returns ErrDuplicateISBN when the ISBN
+// belongs to a book that is not deleted.
+func (r *BookRepository) restoreDeleted(tx *sql.Tx, book *models.Book) error {
+	query := `
+		UPDATE books
+		SET title = ?, author = ?, publisher = ?, publication_year = ?,
+			description = ?, category = ?, language = ?, page_count = ?,
+			location = ?, cover_image_url = ?, deleted_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE isbn = ? AND deleted_at IS NOT NULL`
+
+	args := []interface{}{
+		book.Title, book.Author, book.Publisher, book.PublicationYear,
+		book.Description, book.Category, book.Language, book.PageCount,
+		book.Location, book.CoverImageURL, book.ISBN,
+	}
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrDuplicateISBN
+	}
+
+	idQuery := `SELECT id FROM books WHERE isbn = ?`
+	if tx != nil {
+		err = tx.QueryRow(idQuery, book.ISBN).Scan(&book.ID)
+	} else {
+		err = r.db.QueryRow(idQuery, book.ISBN).Scan(&book.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := syncBookCounts(r.db, tx, book.ID); err != nil {
+		return err
+	}
+
+	countQuery := `SELECT total_copies, available_copies FROM books WHERE id = ?`
+	if tx != nil {
+		return tx.QueryRow(countQuery, book.ID).Scan(&book.TotalCopies, &book.AvailableCopies)
+	}
+	return r.db.QueryRow(countQuery, book.ID).Scan(&book.TotalCopies, &book.AvailableCopies)
+}
+
+// Update updates an existing book's catalog data.
+// Copy counts are maintained by circulation and are not changed here.
+func (r *BookRepository) Update(tx *sql.Tx, book *models.Book) error {
+	query := `
+		UPDATE books
+		SET isbn = ?, title = ?, author = ?, publisher = ?, publication_year = ?,
+			description = ?, category = ?, language = ?, page_count = ?,
+			location = ?, cover_image_url = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL`
+
+	args := []interface{}{
+		book.ISBN, book.Title, book.Author, book.Publisher, book.PublicationYear,
+		book.Description, book.Category, book.Language, book.PageCount,
+		book.Location, book.CoverImageURL, book.ID,
+	}
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return models.ErrDuplicateISBN
+		}
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrBookNotFound
+	}
+
+	return nil
+}
+
+// Delete soft deletes a book so that borrowing history stays intact
+func (r *BookRepository) Delete(tx *sql.Tx, id int64) error {
+	query := `
+		UPDATE books
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, id)
+	} else {
+		result, err = r.db.Exec(query, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrBookNotFound
+	}
+
+	return nil
+}
+
+// List retrieves books with pagination and filters
+func (r *BookRepository) List(page, pageSize int, filters models.BookFilters) ([]*models.Book, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	// Base query
+	baseQuery := `
+		SELECT id, isbn, title, author, publisher, publication_year,
+			   description, category, language, page_count, total_copies,
+			   available_copies, location, cover_image_url, created_at, updated_at
+		FROM books
+		WHERE deleted_at IS NULL`
+
+	// Build query with filters
+	query := baseQuery
+	args := []interface{}{}
+
+	if filters.Search != "" {
+		query += " AND (title LIKE ? OR author LIKE ? OR isbn LIKE ?)"
+		search := "%" + filters.Search + "%"
+		args = append(args, search, search, search)
+	}
+
+	if filters.Category != "" {
+		query += " AND category = ?"
+		args = append(args, filters.Category)
+	}
+
+	if filters.Language != "" {
+		query += " AND language = ?"
+		args = append(args, filters.Language)
+	}
+
+	if filters.Author != "" {
+		query += " AND author LIKE ?"
+		args = append(args, "%"+filters.Author+"%")
+	}
+
+	if filters.YearFrom > 0 {
+		query += " AND publication_year >= ?"
+		args = append(args, filters.YearFrom)
+	}
+
+	if filters.YearTo > 0 {
+		query += " AND publication_year <= ?"
+		args = append(args, filters.YearTo)
+	}
+
+	if filters.Available != nil {
+		if *filters.Available {
+			query += " AND available_copies > 0"
+		} else {
+			query += " AND available_copies = 0"
+		}
+	}
+
+	// Add sorting
+	query += " ORDER BY title ASC, id ASC"
+
+	// Add pagination
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, pageSize, offset)
+
+	// Execute query
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []*models.Book
+	for rows.Next() {
+		var book models.Book
+
+		err := rows.Scan(
+			&book.ID, &book.ISBN, &book.Title, &book.Author, &book.Publisher,
+			&book.PublicationYear, &book.Description, &book.Category, &book.Language,
+			&book.PageCount, &book.TotalCopies, &book.AvailableCopies, &book.Location,
+			&book.CoverImageURL, &book.CreatedAt, &book.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		books = append(books, &book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// Count returns the total number of books that match the given filters
+func (r *BookRepository) Count(filters models.BookFilters) (int, error) {
+	// Base query
+	baseQuery := `
+		SELECT COUNT(*)
+		FROM books
+		WHERE deleted_at IS NULL`
+
+	// Build query with filters
+	query := baseQuery
+	args := []interface{}{}
+
+	if filters.Search != "" {
+		query += " AND (title LIKE ? OR author LIKE ? OR isbn LIKE ?)"
+		search := "%" + filters.Search + "%"
+		args = append(args, search, search, search)
+	}
+
+	if filters.Category != "" {
+		query += " AND category = ?"
+		args = append(args, filters.Category)
+	}
+
+	if filters.Language != "" {
+		query += " AND language = ?"
+		args = append(args, filters.Language)
+	}
+
+	if filters.Author != "" {
+		query += " AND author LIKE ?"
+		args = append(args, "%"+filters.Author+"%")
+	}
+
+	if filters.YearFrom > 0 {
+		query += " AND publication_year >= ?"
+		args = append(args, filters.YearFrom)
+	}
+
+	if filters.YearTo > 0 {
+		query += " AND publication_year <= ?"
+		args = append(args, filters.YearTo)
+	}
+
+	if filters.Available != nil {
+		if *filters.Available {
+			query += " AND available_copies > 0"
+		} else {
+			query += " AND available_copies = 0"
+		}
+	}
+
+	var count int
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
@@ -18,27 +18,41 @@ func NewBorrowingRepository(db *Database) *BorrowingRepository {
 	return &BorrowingRepository{db: db}
 }
 
+// borrowingByIDQuery selects one borrowing with its book and borrower
+const borrowingByIDQuery = `
+	SELECT b.id, b.user_id, b.book_copy_id, b.borrowed_date, b.due_date,
+	       b.returned_date, b.status, b.fine_amount, b.fine_paid,
+	       b.staff_id_checkout, b.staff_id_return, b.notes,
+	       bc.book_id, bk.title, bk.author,
+	       COALESCE(u.full_name, '') as user_name
+	FROM borrowings b
+	JOIN book_copies bc ON b.book_copy_id = bc.id
+	JOIN books bk ON bc.book_id = bk.id
+	LEFT JOIN users u ON b.user_id = u.id
+	WHERE b.id = ?`
+
 // GetByID retrieves a borrowing record by ID
 func (r *BorrowingRepository) GetByID(id int64) (*models.Borrowing, error) {
-	query := `
-		SELECT b.id, b.user_id, b.book_copy_id, b.borrowed_date, b.due_date,
-		       b.returned_date, b.status, b.fine_amount, b.fine_paid,
-		       b.staff_id_checkout, b.staff_id_return, b.notes,
-		       bc.book_id, bk.title, bk.author,
-		       u.full_name as user_name
-		FROM borrowings b
-		JOIN book_copies bc ON b.book_copy_id = bc.id
-		JOIN books bk ON bc.book_id = bk.id
-		JOIN users u ON b.user_id = u.id
-		WHERE b.id = ?`
+	return scanBorrowing(r.db.QueryRow(borrowingByIDQuery, id))
+}
 
+// Reload retrieves a borrowing record inside a transaction, including the
+// changes made in it
+func (r *BorrowingRepository) Reload(tx *sql.Tx, id int64) (*models.Borrowing, error) {
+	return scanBorrowing(tx.QueryRow(borrowingByIDQuery, id))
+}
+
+// scanBorrowing reads a borrowing selected with borrowingByIDQuery. Loans
+// unlinked from their patron have a zero UserID.
+func scanBorrowing(row rowScanner) (*models.Borrowing, error) {
 	var borrowing models.Borrowing
+	var userID sql.NullInt64
 	var returnedDate sql.NullTime
 	var staffIDCheckout, staffIDReturn sql.NullInt64
 	var notes sql.NullString
 
-	err := r.db.QueryRow(query, id).Scan(
-		&borrowing.ID, &borrowing.UserID, &borrowing.BookCopyID, &borrowing.BorrowedDate,
+	err := row.Scan(
+		&borrowing.ID, &userID, &borrowing.BookCopyID, &borrowing.BorrowedDate,
 		&borrowing.DueDate, &returnedDate, &borrowing.Status, &borrowing.FineAmount,
 		&borrowing.FinePaid, &staffIDCheckout, &staffIDReturn, &notes,
 		&borrowing.BookID, &borrowing.BookTitle, &borrowing.BookAuthor,
@@ -52,6 +66,7 @@ func (r *BorrowingRepository) GetByID(id int64) (*models.Borrowing, error) {
 		return nil, err
 	}
 
+	borrowing.UserID = userID.Int64
 	if returnedDate.Valid {
 		borrowing.ReturnedDate = &returnedDate.Time
 	}
@@ -68,33 +83,27 @@ func (r *BorrowingRepository) GetByID(id int64) (*models.Borrowing, error) {
 	return &borrowing, nil
 }
 
-// Create adds a new borrowing record
+// Create adds a new borrowing record and records a loan.created event.
+// When tx is nil the insert runs in its own transaction.
 func (r *BorrowingRepository) Create(tx *sql.Tx, borrowing *models.Borrowing) error {
+	if tx == nil {
+		return r.db.Transaction(func(tx *sql.Tx) error {
+			return r.Create(tx, borrowing)
+		})
+	}
+
 	query := `
 		INSERT INTO borrowings (
 			user_id, book_copy_id, borrowed_date, due_date, status,
 			staff_id_checkout, notes
 		) VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-	var result sql.Result
-	var err error
-
-	if tx != nil {
-		result, err = tx.Exec(
-			query,
-			borrowing.UserID, borrowing.BookCopyID, borrowing.BorrowedDate,
-			borrowing.DueDate, borrowing.Status, borrowing.StaffIDCheckout,
-			borrowing.Notes,
-		)
-	} else {
-		result, err = r.db.Exec(
-			query,
-			borrowing.UserID, borrowing.BookCopyID, borrowing.BorrowedDate,
-			borrowing.DueDate, borrowing.Status, borrowing.StaffIDCheckout,
-			borrowing.Notes,
-		)
-	}
-
+	result, err := tx.Exec(
+		query,
+		borrowing.UserID, borrowing.BookCopyID, borrowing.BorrowedDate,
+		borrowing.DueDate, borrowing.Status, borrowing.StaffIDCheckout,
+		borrowing.Notes,
+	)
 	if err != nil {
 		return err
 	}
@@ -105,99 +114,456 @@ func (r *BorrowingRepository) Create(tx *sql.Tx, borrowing *models.Borrowing) er
 	}
 
 	borrowing.ID = id
-	return nil
+
+	return appendEvent(tx, models.EventLoanCreated, models.AggregateBorrowing, borrowing.ID, map[string]interface{}{
+		"borrowing_id":  borrowing.ID,
+		"user_id":       borrowing.UserID,
+		"book_copy_id":  borrowing.BookCopyID,
+		"borrowed_date": borrowing.BorrowedDate,
+		"due_date":      borrowing.DueDate,
+	})
 }
 
-// UpdateBookCopyStatus updates the status of a book copy
-func (r *BorrowingRepository) UpdateBookCopyStatus(tx *sql.Tx, bookCopyID int64, status string) error {
-	query := `UPDATE book_copies SET status = ? WHERE id = ?`
+// CountActiveByUser counts a user's loans that have not been returned yet
+func (r *BorrowingRepository) CountActiveByUser(tx *sql.Tx, userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM borrowings
+		WHERE user_id = ? AND returned_date IS NULL`
 
+	var count int
 	var err error
 	if tx != nil {
-		_, err = tx.Exec(query, status, bookCopyID)
+		err = tx.QueryRow(query, userID).Scan(&count)
 	} else {
-		_, err = r.db.Exec(query, status, bookCopyID)
+		err = r.db.QueryRow(query, userID).Scan(&count)
 	}
 
-	return err
+	return count, err
 }
 
-// UpdateBookAvailableCopies updates the available copies count for a book
-func (r *BorrowingRepository) UpdateBookAvailableCopies(tx *sql.Tx, bookID int64, increment bool) error {
-	var query string
-	if increment {
-		query = `UPDATE books SET available_copies = available_copies + 1 WHERE id = ?`
-	} else {
-		query = `UPDATE books SET available_copies = available_copies - 1 WHERE id = ? AND available_copies > 0`
-	}
+// SumAccruingFines returns the fines accrued so far on a user's loans that
+// are still out. They are charged to the ledger only on return.
+func (r *BorrowingRepository) SumAccruingFines(tx *sql.Tx, userID int64) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(fine_amount), 0)
+		FROM borrowings
+		WHERE user_id = ? AND returned_date IS NULL AND fine_amount > 0`
 
-	var result sql.Result
+	var total float64
 	var err error
-
 	if tx != nil {
-		result, err = tx.Exec(query, bookID)
+		err = tx.QueryRow(query, userID).Scan(&total)
 	} else {
-		result, err = r.db.Exec(query, bookID)
+		err = r.db.QueryRow(query, userID).Scan(&total)
 	}
 
+	return total, err
+}
+
+// MarkFinePaid flags the fine on a borrowing as settled
+func (r *BorrowingRepository) MarkFinePaid(tx *sql.Tx, borrowingID int64) error {
+	query := `
+		UPDATE borrowings
+		SET fine_paid = TRUE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND fine_amount > 0`
+
+	_, err := tx.Exec(query, borrowingID)
+	return err
+}
+
+// ListUnpaidFines locks a user's returned borrowings whose fines are not
+// yet settled and returns their IDs. Loans that are still out are skipped, as
+// their fines are not on the ledger until they are returned.
+func (r *BorrowingRepository) ListUnpaidFines(tx *sql.Tx, userID int64) ([]int64, error) {
+	query := `
+		SELECT id
+		FROM borrowings
+		WHERE user_id = ? AND fine_amount > 0 AND fine_paid = FALSE
+		  AND returned_date IS NOT NULL
+		FOR UPDATE`
+
+	rows, err := tx.Query(query, userID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// LockOpenLoan locks an unreturned borrowing until the transaction ends and returns its
+// current due date and the ID of the borrowed book
+func (r *BorrowingRepository) LockOpenLoan(tx *sql.Tx, borrowingID int64) (time.Time, int64, error) {
+	query := `
+		SELECT b.due_date, bc.book_id
+		FROM borrowings b
+		JOIN book_copies bc ON b.book_copy_id = bc.id
+		WHERE b.id = ? AND b.returned_date IS NULL
+		FOR UPDATE`
+
+	var dueDate time.Time
+	var bookID int64
+	err := tx.QueryRow(query, borrowingID).Scan(&dueDate, &bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, 0, models.ErrBorrowingAlreadyReturned
+		}
+		return time.Time{}, 0, err
 	}
 
-	rows, err := result.RowsAffected()
+	return dueDate, bookID, nil
+}
+
+// UpdateDueDate moves a borrowing's due date and makes it active again
+func (r *BorrowingRepository) UpdateDueDate(tx *sql.Tx, borrowingID int64, dueDate time.Time) error {
+	query := `
+		UPDATE borrowings
+		SET due_date = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := tx.Exec(query, dueDate, models.BorrowingStatusActive, borrowingID)
+	return err
+}
+
+// CreateRenewal records a renewal of a borrowing
+func (r *BorrowingRepository) CreateRenewal(tx *sql.Tx, renewal *models.LoanRenewal) error {
+	query := `
+		INSERT INTO loan_renewals (borrowing_id, previous_due_date, new_due_date, renewed_by)
+		VALUES (?, ?, ?, ?)`
+
+	result, err := tx.Exec(query, renewal.BorrowingID, renewal.PreviousDueDate, renewal.NewDueDate, renewal.RenewedBy)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 && !increment {
-		return models.ErrNoAvailableCopies
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
 	}
 
+	renewal.ID = id
 	return nil
 }
 
-// Return marks a borrowing as returned
-func (r *BorrowingRepository) Return(borrowingID int64, returnedDate time.Time, staffID int64, fineAmount float64) error {
-	// Start a transaction
-	return r.db.Transaction(func(tx *sql.Tx) error {
-		// Get the borrowing record
+// CountRenewals counts how many times a borrowing has been renewed
+func (r *BorrowingRepository) CountRenewals(tx *sql.Tx, borrowingID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM loan_renewals WHERE borrowing_id = ?`
+
+	var count int
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, borrowingID).Scan(&count)
+	} else {
+		err = r.db.QueryRow(query, borrowingID).Scan(&count)
+	}
+
+	return count, err
+}
+
+// ListRenewals retrieves the renewal history of a borrowing
+func (r *BorrowingRepository) ListRenewals(borrowingID int64) ([]*models.LoanRenewal, error) {
+	query := `
+		SELECT id, borrowing_id, previous_due_date, new_due_date, renewed_by, created_at
+		FROM loan_renewals
+		WHERE borrowing_id = ?
+		ORDER BY created_at, id`
+
+	rows, err := r.db.Query(query, borrowingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var renewals []*models.LoanRenewal
+	for rows.Next() {
+		var renewal models.LoanRenewal
+		var renewedBy sql.NullInt64
+
+		err := rows.Scan(
+			&renewal.ID, &renewal.BorrowingID, &renewal.PreviousDueDate,
+			&renewal.NewDueDate, &renewedBy, &renewal.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if renewedBy.Valid {
+			renewal.RenewedBy = &renewedBy.Int64
+		}
+
+		renewals = append(renewals, &renewal)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return renewals, nil
+}
+
+// HasHoldsByOthers reports whether any other patron is waiting for a book
+func (r *BorrowingRepository) HasHoldsByOthers(tx *sql.Tx, bookID, userID int64) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM reservations
+		WHERE book_id = ? AND user_id <> ? AND status = 'pending'`
+
+	var count int
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, bookID, userID).Scan(&count)
+	} else {
+		err = r.db.QueryRow(query, bookID, userID).Scan(&count)
+	}
+
+	return count > 0, err
+}
+
+// MarkOverdue moves active loans that are past their due date to overdue
+// and records a loan.overdue event for each of them
+func (r *BorrowingRepository) MarkOverdue(now time.Time) (int64, error) {
+	var marked int64
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
 		query := `
-			SELECT b.book_copy_id, bc.book_id
-			FROM borrowings b
-			JOIN book_copies bc ON b.book_copy_id = bc.id
-			WHERE b.id = ?`
+			SELECT id, user_id, due_date
+			FROM borrowings
+			WHERE status = ? AND returned_date IS NULL AND due_date < ?
+			FOR UPDATE`
 
-		var bookCopyID, bookID int64
-		err := tx.QueryRow(query, borrowingID).Scan(&bookCopyID, &bookID)
+		rows, err := tx.Query(query, models.BorrowingStatusActive, now)
 		if err != nil {
 			return err
 		}
 
-		// Update the borrowing record
+		var overdue []models.Borrowing
+		for rows.Next() {
+			var borrowing models.Borrowing
+			if err := rows.Scan(&borrowing.ID, &borrowing.UserID, &borrowing.DueDate); err != nil {
+				rows.Close()
+				return err
+			}
+			overdue = append(overdue, borrowing)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
 		updateQuery := `
 			UPDATE borrowings
-			SET returned_date = ?, staff_id_return = ?, status = ?,
-				fine_amount = ?, updated_at = CURRENT_TIMESTAMP
+			SET status = ?, updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?`
 
-		status := models.BorrowingStatusReturned
-		if fineAmount > 0 {
-			status = models.BorrowingStatusOverdue
+		for _, borrowing := range overdue {
+			if _, err := tx.Exec(updateQuery, models.BorrowingStatusOverdue, borrowing.ID); err != nil {
+				return err
+			}
+
+			err := appendEvent(tx, models.EventLoanOverdue, models.AggregateBorrowing, borrowing.ID, map[string]interface{}{
+				"borrowing_id": borrowing.ID,
+				"user_id":      borrowing.UserID,
+				"due_date":     borrowing.DueDate,
+			})
+			if err != nil {
+				return err
+			}
 		}
 
-		_, err = tx.Exec(updateQuery, returnedDate, staffID, status, fineAmount, borrowingID)
+		marked = int64(len(overdue))
+		return nil
+	})
+
+	return marked, err
+}
+
+// ListUnreturnedOverdue retrieves overdue loans that are still out.
+// Only loans due before dueBefore are returned.
+func (r *BorrowingRepository) ListUnreturnedOverdue(dueBefore time.Time) ([]*models.Borrowing, error) {
+	query := `
+		SELECT b.id, b.user_id, b.book_copy_id, b.borrowed_date, b.due_date,
+			b.status, b.fine_amount, bc.book_id, bk.title
+		FROM borrowings b
+		JOIN book_copies bc ON b.book_copy_id = bc.id
+		JOIN books bk ON bc.book_id = bk.id
+		WHERE b.status = ? AND b.returned_date IS NULL AND b.due_date < ?
+		ORDER BY b.due_date`
+
+	return r.listOpenLoans(query, models.BorrowingStatusOverdue, dueBefore)
+}
+
+// ListDueBetween retrieves active loans due between from and to
+func (r *BorrowingRepository) ListDueBetween(from, to time.Time) ([]*models.Borrowing, error) {
+	query := `
+		SELECT b.id, b.user_id, b.book_copy_id, b.borrowed_date, b.due_date,
+			b.status, b.fine_amount, bc.book_id, bk.title
+		FROM borrowings b
+		JOIN book_copies bc ON b.book_copy_id = bc.id
+		JOIN books bk ON bc.book_id = bk.id
+		WHERE b.status = ? AND b.returned_date IS NULL AND b.due_date BETWEEN ? AND ?
+		ORDER BY b.due_date`
+
+	return r.listOpenLoans(query, models.BorrowingStatusActive, from, to)
+}
+
+// listOpenLoans runs a query selecting the columns of ListUnreturnedOverdue
+func (r *BorrowingRepository) listOpenLoans(query string, args ...interface{}) ([]*models.Borrowing, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var borrowings []*models.Borrowing
+	for rows.Next() {
+		var borrowing models.Borrowing
+
+		err := rows.Scan(
+			&borrowing.ID, &borrowing.UserID, &borrowing.BookCopyID,
+			&borrowing.BorrowedDate, &borrowing.DueDate, &borrowing.Status,
+			&borrowing.FineAmount, &borrowing.BookID, &borrowing.BookTitle,
+		)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		// Update book copy status
-		err = r.UpdateBookCopyStatus(tx, bookCopyID, models.BookCopyStatusAvailable)
-		if err != nil {
-			return err
+		borrowings = append(borrowings, &borrowing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return borrowings, nil
+}
+
+// UpdateFineAmount stores the fine accrued so far on a loan that is still out
+func (r *BorrowingRepository) UpdateFineAmount(tx *sql.Tx, borrowingID int64, fineAmount float64) error {
+	query := `
+		UPDATE borrowings
+		SET fine_amount = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND returned_date IS NULL`
+
+	_, err := tx.Exec(query, fineAmount, borrowingID)
+	return err
+}
+
+// MarkLost flags an overdue loan as presumed lost
+func (r *BorrowingRepository) MarkLost(tx *sql.Tx, borrowingID int64) error {
+	query := `
+		UPDATE borrowings
+		SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ? AND returned_date IS NULL`
+
+	_, err := tx.Exec(query, models.BorrowingStatusLost, borrowingID, models.BorrowingStatusOverdue)
+	return err
+}
+
+// UpdateBookCopyStatus updates the status of a book copy and records a
+// copy.status_changed event. When tx is nil the update runs in its own
+// transaction.
+func (r *BorrowingRepository) UpdateBookCopyStatus(tx *sql.Tx, bookCopyID int64, status string) error {
+	if tx == nil {
+		return r.db.Transaction(func(tx *sql.Tx) error {
+			return r.UpdateBookCopyStatus(tx, bookCopyID, status)
+		})
+	}
+
+	query := `UPDATE book_copies SET status = ? WHERE id = ?`
+
+	if _, err := tx.Exec(query, status, bookCopyID); err != nil {
+		return err
+	}
+
+	return appendEvent(tx, models.EventCopyStatusChanged, models.AggregateBookCopy, bookCopyID, map[string]interface{}{
+		"book_copy_id": bookCopyID,
+		"status":       status,
+	})
+}
+
+// SyncBookCounts recalculates a book's total and available copies after
+// circulation changes the status of one of its copies
+func (r *BorrowingRepository) SyncBookCounts(tx *sql.Tx, bookID int64) error {
+	return syncBookCounts(r.db, tx, bookID)
+}
+
+// Return marks a borrowing as returned.
+// When tx is nil the return runs in its own transaction.
+func (r *BorrowingRepository) Return(tx *sql.Tx, borrowingID int64, returnedDate time.Time, staffID int64, fineAmount float64) error {
+	if tx == nil {
+		return r.db.Transaction(func(tx *sql.Tx) error {
+			return r.Return(tx, borrowingID, returnedDate, staffID, fineAmount)
+		})
+	}
+
+	// Get the borrowing record
+	query := `
+		SELECT b.user_id, b.book_copy_id, bc.book_id, b.returned_date
+		FROM borrowings b
+		JOIN book_copies bc ON b.book_copy_id = bc.id
+		WHERE b.id = ?
+		FOR UPDATE`
+
+	var userID, bookCopyID, bookID int64
+	var alreadyReturned sql.NullTime
+	err := tx.QueryRow(query, borrowingID).Scan(&userID, &bookCopyID, &bookID, &alreadyReturned)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrBorrowingNotFound
 		}
+		return err
+	}
 
-		// Update book available copies
-		return r.UpdateBookAvailableCopies(tx, bookID, true)
+	if alreadyReturned.Valid {
+		return models.ErrBorrowingAlreadyReturned
+	}
+
+	// Update the borrowing record
+	updateQuery := `
+		UPDATE borrowings
+		SET returned_date = ?, staff_id_return = ?, status = ?,
+			fine_amount = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	status := models.BorrowingStatusReturned
+	if fineAmount > 0 {
+		status = models.BorrowingStatusOverdue
+	}
+
+	_, err = tx.Exec(updateQuery, returnedDate, staffID, status, fineAmount, borrowingID)
+	if err != nil {
+		return err
+	}
+
+	// Update book copy status
+	err = r.UpdateBookCopyStatus(tx, bookCopyID, models.BookCopyStatusAvailable)
+	if err != nil {
+		return err
+	}
+
+	// Update book copy counts
+	if err := r.SyncBookCounts(tx, bookID); err != nil {
+		return err
+	}
+
+	return appendEvent(tx, models.EventLoanReturned, models.AggregateBorrowing, borrowingID, map[string]interface{}{
+		"borrowing_id":    borrowingID,
+		"user_id":         userID,
+		"book_copy_id":    bookCopyID,
+		"returned_date":   returnedDate,
+		"fine_amount":     fineAmount,
+		"staff_id_return": staffID,
 	})
 }
 
@@ -291,11 +657,11 @@ func (r *BorrowingRepository) List(page, pageSize int, filters models.BorrowingF
 		SELECT b.id, b.user_id, b.book_copy_id, b.borrowed_date, b.due_date,
 			b.returned_date, b.status, b.fine_amount, b.fine_paid,
 			bc.book_id, bk.title, bk.author,
-			u.full_name as user_name
+			COALESCE(u.full_name, '') as user_name
 		FROM borrowings b
 		JOIN book_copies bc ON b.book_copy_id = bc.id
 		JOIN books bk ON bc.book_id = bk.id
-		JOIN users u ON b.user_id = u.id
+		LEFT JOIN users u ON b.user_id = u.id
 		WHERE 1=1`
 
 	// Build query with filters
@@ -348,10 +714,11 @@ func (r *BorrowingRepository) List(page, pageSize int, filters models.BorrowingF
 	var borrowings []*models.Borrowing
 	for rows.Next() {
 		var borrowing models.Borrowing
+		var userID sql.NullInt64
 		var returnedDate sql.NullTime
 
 		err := rows.Scan(
-			&borrowing.ID, &borrowing.UserID, &borrowing.BookCopyID,
+			&borrowing.ID, &userID, &borrowing.BookCopyID,
 			&borrowing.BorrowedDate, &borrowing.DueDate, &returnedDate,
 			&borrowing.Status, &borrowing.FineAmount, &borrowing.FinePaid,
 			&borrowing.BookID, &borrowing.BookTitle, &borrowing.BookAuthor,
@@ -361,6 +728,7 @@ func (r *BorrowingRepository) List(page, pageSize int, filters models.BorrowingF
 			return nil, err
 		}
 
+		borrowing.UserID = userID.Int64
 		if returnedDate.Valid {
 			borrowing.ReturnedDate = &returnedDate.Time
 		}
@@ -383,7 +751,7 @@ func (r *BorrowingRepository) Count(filters models.BorrowingFilters) (int, error
 		FROM borrowings b
 		JOIN book_copies bc ON b.book_copy_id = bc.id
 		JOIN books bk ON bc.book_id = bk.id
-		JOIN users u ON b.user_id = u.id
+		LEFT JOIN users u ON b.user_id = u.id
 		WHERE 1=1`
 
 	// Build query with filters
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"library-management-system/internal/models"
+)
+
+// CirculationPolicyRepository handles database operations for circulation policies
+type CirculationPolicyRepository struct {
+	db *Database
+}
+
+// NewCirculationPolicyRepository creates a new CirculationPolicyRepository instance
+func NewCirculationPolicyRepository(db *Database) *CirculationPolicyRepository {
+	return &CirculationPolicyRepository{db: db}
+}
+
+// scanPolicy reads a circulation policy row
+func scanPolicy(row rowScanner) (*models.CirculationPolicy, error) {
+	var policy models.CirculationPolicy
+	var updatedBy sql.NullInt64
+
+	err := row.Scan(
+		&policy.ID, &policy.UserRole, &policy.Category, &policy.LoanPeriodDays,
+		&policy.MaxLoans, &policy.MaxRenewals, &policy.DailyFine, &policy.FineCap,
+		&policy.GraceDays, &updatedBy, &policy.CreatedAt, &policy.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrPolicyNotFound
+		}
+		return nil, err
+	}
+
+	if updatedBy.Valid {
+		policy.UpdatedBy = &updatedBy.Int64
+	}
+
+	return &policy, nil
+}
+
+// GetByID retrieves a circulation policy by ID
+func (r *CirculationPolicyRepository) GetByID(id int64) (*models.CirculationPolicy, error) {
+	query := `
+		SELECT id, user_role, category, loan_period_days, max_loans, max_renewals,
+		       daily_fine, fine_cap, grace_days, updated_by, created_at, updated_at
+		FROM circulation_policies
+		WHERE id = ?`
+
+	return scanPolicy(r.db.QueryRow(query, id))
+}
+
+// Resolve returns the policy for a role and book category, falling back to
+// the role's default policy when the category has no rules of its own
+func (r *CirculationPolicyRepository) Resolve(tx *sql.Tx, role models.UserRole, category string) (*models.CirculationPolicy, error) {
+	query := `
+		SELECT id, user_role, category, loan_period_days, max_loans, max_renewals,
+		       daily_fine, fine_cap, grace_days, updated_by, created_at, updated_at
+		FROM circulation_policies
+		WHERE user_role = ? AND category IN (?, '')
+		ORDER BY category = '' ASC
+		LIMIT 1`
+
+	if tx != nil {
+		return scanPolicy(tx.QueryRow(query, role, category))
+	}
+	return scanPolicy(r.db.QueryRow(query, role, category))
+}
+
+// ResolveForBorrowing returns the policy that governs an existing borrowing,
+// based on the borrower's role and the category of the borrowed book
+func (r *CirculationPolicyRepository) ResolveForBorrowing(tx *sql.Tx, borrowingID int64) (*models.CirculationPolicy, error) {
+	query := `
+		SELECT p.id, p.user_role, p.category, p.loan_period_days, p.max_loans,
+		       p.max_renewals, p.daily_fine, p.fine_cap, p.grace_days, p.updated_by,
+		       p.created_at, p.updated_at
+		FROM borrowings b
+		JOIN users u ON b.user_id = u.id
+		JOIN book_copies bc ON b.book_copy_id = bc.id
+		JOIN books bk ON bc.book_id = bk.id
+		JOIN circulation_policies p
+			ON p.user_role = u.role AND p.category IN (COALESCE(bk.category, ''), '')
+		WHERE b.id = ?
+		ORDER BY p.category = '' ASC
+		LIMIT 1`
+
+	if tx != nil {
+		return scanPolicy(tx.QueryRow(query, borrowingID))
+	}
+	return scanPolicy(r.db.QueryRow(query, borrowingID))
+}
+
+// List retrieves all circulation policies
+func (r *CirculationPolicyRepository) List() ([]*models.CirculationPolicy, error) {
+	query := `
+		SELECT id, user_role, category, loan_period_days, max_loans, max_renewals,
+		       daily_fine, fine_cap, grace_days, updated_by, created_at, updated_at
+		FROM circulation_policies
+		ORDER BY user_role, category`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*models.CirculationPolicy
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// Save creates the policy for its role and category, or replaces the existing one
+func (r *CirculationPolicyRepository) Save(policy *models.CirculationPolicy) error {
+	query := `
+		INSERT INTO circulation_policies (
+			user_role, category, loan_period_days, max_loans, max_renewals,
+			daily_fine, fine_cap, grace_days, updated_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			id = LAST_INSERT_ID(id),
+			loan_period_days = VALUES(loan_period_days),
+			max_loans = VALUES(max_loans),
+			max_renewals = VALUES(max_renewals),
+			daily_fine = VALUES(daily_fine),
+			fine_cap = VALUES(fine_cap),
+			grace_days = VALUES(grace_days),
+			updated_by = VALUES(updated_by),
+			updated_at = CURRENT_TIMESTAMP`
+
+	result, err := r.db.Exec(
+		query,
+		policy.UserRole, policy.Category, policy.LoanPeriodDays, policy.MaxLoans,
+		policy.MaxRenewals, policy.DailyFine, policy.FineCap, policy.GraceDays,
+		policy.UpdatedBy,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	policy.ID = id
+	return nil
+}
+
+// Delete removes a circulation policy
+func (r *CirculationPolicyRepository) Delete(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM circulation_policies WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrPolicyNotFound
+	}
+
+	return nil
+}
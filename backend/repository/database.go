@@ -2,14 +2,18 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"library-management-system/internal/config"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
+// mysqlDuplicateEntry is the MySQL error number for unique key violations
+const mysqlDuplicateEntry = 1062
+
 // Database represents a database connection
 type Database struct {
 	*sql.DB
@@ -75,3 +79,9 @@ func (db *Database) Transaction(fn func(*sql.Tx) error) error {
 
 	return tx.Commit()
 }
+
+// isDuplicateEntry reports whether err is a unique key violation
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+
+	"library-management-system/internal/models"
+)
+
+// FineRepository handles database operations for fine assessments
+type FineRepository struct {
+	db *Database
+}
+
+// NewFineRepository creates a new FineRepository instance
+func NewFineRepository(db *Database) *FineRepository {
+	return &FineRepository{db: db}
+}
+
+// CreateAssessment records a fine calculation
+func (r *FineRepository) CreateAssessment(tx *sql.Tx, assessment *models.FineAssessment) error {
+	query := `
+		INSERT INTO fine_assessments (
+			borrowing_id, user_id, policy_id, due_date, assessed_until, days_late,
+			closed_days, grace_days, chargeable_days, daily_rate, fine_cap, amount
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	args := []interface{}{
+		assessment.BorrowingID, assessment.UserID, assessment.PolicyID,
+		assessment.DueDate, assessment.AssessedUntil, assessment.DaysLate,
+		assessment.ClosedDays, assessment.GraceDays, assessment.ChargeableDays,
+		assessment.DailyRate, assessment.FineCap, assessment.Amount,
+	}
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	assessment.ID = id
+	return nil
+}
+
+// fineAssessmentColumns are the columns scanned by listAssessments
+const fineAssessmentColumns = `
+	id, borrowing_id, COALESCE(user_id, 0), COALESCE(policy_id, 0), due_date,
+	assessed_until, days_late, closed_days, grace_days, chargeable_days,
+	daily_rate, fine_cap, amount, created_at`
+
+// ListAssessmentsByBorrowing retrieves the fine calculations made for a borrowing
+func (r *FineRepository) ListAssessmentsByBorrowing(borrowingID int64) ([]*models.FineAssessment, error) {
+	query := `SELECT ` + fineAssessmentColumns + `
+		FROM fine_assessments
+		WHERE borrowing_id = ?
+		ORDER BY created_at, id`
+
+	return r.listAssessments(query, borrowingID)
+}
+
+// ListAssessmentsByUser retrieves every fine calculation made for a patron
+func (r *FineRepository) ListAssessmentsByUser(userID int64) ([]*models.FineAssessment, error) {
+	query := `SELECT ` + fineAssessmentColumns + `
+		FROM fine_assessments
+		WHERE user_id = ?
+		ORDER BY created_at, id`
+
+	return r.listAssessments(query, userID)
+}
+
+// listAssessments runs a query selecting fineAssessmentColumns
+func (r *FineRepository) listAssessments(query string, args ...interface{}) ([]*models.FineAssessment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assessments []*models.FineAssessment
+	for rows.Next() {
+		var assessment models.FineAssessment
+
+		err := rows.Scan(
+			&assessment.ID, &assessment.BorrowingID, &assessment.UserID,
+			&assessment.PolicyID, &assessment.DueDate, &assessment.AssessedUntil,
+			&assessment.DaysLate, &assessment.ClosedDays, &assessment.GraceDays,
+			&assessment.ChargeableDays, &assessment.DailyRate, &assessment.FineCap,
+			&assessment.Amount, &assessment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		assessments = append(assessments, &assessment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assessments, nil
+}
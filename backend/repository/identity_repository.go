@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// IdentityRepository handles database operations for external identities
+// and pending single sign-on requests
+type IdentityRepository struct {
+	db *Database
+}
+
+// NewIdentityRepository creates a new IdentityRepository instance
+func NewIdentityRepository(db *Database) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetBySubject retrieves the identity of a provider subject. It returns nil
+// when the subject has never signed in.
+func (r *IdentityRepository) GetBySubject(issuer, subject string) (*models.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, issuer, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = ? AND subject = ?`
+
+	var identity models.UserIdentity
+	var email sql.NullString
+	var lastLoginAt sql.NullTime
+
+	err := r.db.QueryRow(query, issuer, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Issuer, &identity.Subject,
+		&email, &identity.CreatedAt, &lastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	identity.Email = email.String
+	if lastLoginAt.Valid {
+		identity.LastLoginAt = &lastLoginAt.Time
+	}
+
+	return &identity, nil
+}
+
+// Create links a provider subject to a local account
+func (r *IdentityRepository) Create(tx *sql.Tx, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, identity.UserID, identity.Issuer, identity.Subject, identity.Email)
+	} else {
+		result, err = r.db.Exec(query, identity.UserID, identity.Issuer, identity.Subject, identity.Email)
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	identity.ID = id
+	return nil
+}
+
+// RecordLogin stores the time and email of a sign-in through an identity
+func (r *IdentityRepository) RecordLogin(id int64, email string) error {
+	query := `
+		UPDATE user_identities
+		SET email = ?, last_login_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, email, id)
+	return err
+}
+
+// CreateState stores a pending authorization request
+func (r *IdentityRepository) CreateState(state *models.SSOLoginState) error {
+	query := `
+		INSERT INTO oidc_login_states (state, code_verifier, nonce, expires_at)
+		VALUES (?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, state.State, state.CodeVerifier, state.Nonce, state.ExpiresAt)
+	return err
+}
+
+// ConsumeState removes and returns a pending authorization request, so each
+// one can complete only once. Expired requests are reported as unknown.
+func (r *IdentityRepository) ConsumeState(state string, now time.Time) (*models.SSOLoginState, error) {
+	var pending models.SSOLoginState
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			SELECT state, code_verifier, nonce, expires_at
+			FROM oidc_login_states
+			WHERE state = ?
+			FOR UPDATE`, state).Scan(&pending.State, &pending.CodeVerifier, &pending.Nonce, &pending.ExpiresAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrInvalidSSOState
+			}
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM oidc_login_states WHERE state = ?`, state)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !pending.ExpiresAt.After(now) {
+		return nil, models.ErrInvalidSSOState
+	}
+
+	return &pending, nil
+}
+
+// DeleteExpiredStates removes authorization requests that were never completed
+func (r *IdentityRepository) DeleteExpiredStates(now time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM oidc_login_states WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
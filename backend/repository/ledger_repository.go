@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"library-management-system/internal/models"
+)
+
+// LedgerRepository handles database operations for patron account ledgers
+type LedgerRepository struct {
+	db *Database
+}
+
+// NewLedgerRepository creates a new LedgerRepository instance
+func NewLedgerRepository(db *Database) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// CreateEntry adds an entry to a patron's ledger
+func (r *LedgerRepository) CreateEntry(tx *sql.Tx, entry *models.LedgerEntry) error {
+	query := `
+		INSERT INTO patron_ledger_entries (
+			user_id, borrowing_id, entry_type, amount, description,
+			payment_method, receipt_number, staff_id
+		) VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?)`
+
+	args := []interface{}{
+		entry.UserID, entry.BorrowingID, entry.EntryType, entry.Amount,
+		entry.Description, entry.PaymentMethod, entry.ReceiptNumber, entry.StaffID,
+	}
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// SetReceiptNumber stores the receipt number issued for a payment entry
+func (r *LedgerRepository) SetReceiptNumber(tx *sql.Tx, entryID int64, receiptNumber string) error {
+	_, err := tx.Exec(`UPDATE patron_ledger_entries SET receipt_number = ? WHERE id = ?`, receiptNumber, entryID)
+	return err
+}
+
+// Balance sums a patron's ledger
+func (r *LedgerRepository) Balance(tx *sql.Tx, userID int64) (*models.AccountBalance, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN entry_type = 'charge' THEN amount END), 0),
+			COALESCE(SUM(CASE WHEN entry_type = 'payment' THEN amount END), 0),
+			COALESCE(SUM(CASE WHEN entry_type = 'waiver' THEN amount END), 0),
+			COALESCE(SUM(CASE WHEN entry_type = 'charge' THEN amount ELSE -amount END), 0)
+		FROM patron_ledger_entries
+		WHERE user_id = ?`
+
+	balance := models.AccountBalance{UserID: userID}
+
+	// The balance is summed as DECIMAL so settled accounts come out at exactly zero
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, userID).Scan(&balance.Charges, &balance.Payments, &balance.Waivers, &balance.Balance)
+	} else {
+		err = r.db.QueryRow(query, userID).Scan(&balance.Charges, &balance.Payments, &balance.Waivers, &balance.Balance)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &balance, nil
+}
+
+// BorrowingBalance returns the amount still owed on a single borrowing
+func (r *LedgerRepository) BorrowingBalance(tx *sql.Tx, borrowingID int64) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(CASE WHEN entry_type = 'charge' THEN amount ELSE -amount END), 0)
+		FROM patron_ledger_entries
+		WHERE borrowing_id = ?`
+
+	var balance float64
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, borrowingID).Scan(&balance)
+	} else {
+		err = r.db.QueryRow(query, borrowingID).Scan(&balance)
+	}
+
+	return balance, err
+}
+
+// ListByUser retrieves a patron's ledger entries with pagination, newest first
+func (r *LedgerRepository) ListByUser(userID int64, page, pageSize int) ([]*models.LedgerEntry, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT id, user_id, borrowing_id, entry_type, amount, description,
+		       payment_method, receipt_number, staff_id, created_at
+		FROM patron_ledger_entries
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.LedgerEntry
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CountByUser counts a patron's ledger entries
+func (r *LedgerRepository) CountByUser(userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM patron_ledger_entries WHERE user_id = ?`, userID).Scan(&count)
+	return count, err
+}
+
+// GetByReceiptNumber retrieves the payment entry a receipt was issued for
+func (r *LedgerRepository) GetByReceiptNumber(receiptNumber string) (*models.LedgerEntry, error) {
+	query := `
+		SELECT id, user_id, borrowing_id, entry_type, amount, description,
+		       payment_method, receipt_number, staff_id, created_at
+		FROM patron_ledger_entries
+		WHERE receipt_number = ?`
+
+	entry, err := scanLedgerEntry(r.db.QueryRow(query, receiptNumber))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrReceiptNotFound
+	}
+	return entry, err
+}
+
+// scanLedgerEntry reads a ledger entry row
+func scanLedgerEntry(row rowScanner) (*models.LedgerEntry, error) {
+	var entry models.LedgerEntry
+	var borrowingID, staffID sql.NullInt64
+	var description, paymentMethod, receiptNumber sql.NullString
+
+	err := row.Scan(
+		&entry.ID, &entry.UserID, &borrowingID, &entry.EntryType, &entry.Amount,
+		&description, &paymentMethod, &receiptNumber, &staffID, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrowingID.Valid {
+		entry.BorrowingID = &borrowingID.Int64
+	}
+	if staffID.Valid {
+		entry.StaffID = &staffID.Int64
+	}
+	entry.Description = description.String
+	entry.PaymentMethod = paymentMethod.String
+	entry.ReceiptNumber = receiptNumber.String
+
+	return &entry, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// LibraryCalendarRepository handles database operations for library closed days
+type LibraryCalendarRepository struct {
+	db *Database
+}
+
+// NewLibraryCalendarRepository creates a new LibraryCalendarRepository instance
+func NewLibraryCalendarRepository(db *Database) *LibraryCalendarRepository {
+	return &LibraryCalendarRepository{db: db}
+}
+
+// ListClosedDays retrieves the closed days between from and to, inclusive
+func (r *LibraryCalendarRepository) ListClosedDays(tx *sql.Tx, from, to time.Time) ([]*models.ClosedDay, error) {
+	query := `
+		SELECT closed_date, COALESCE(reason, ''), created_at
+		FROM library_closed_days
+		WHERE closed_date BETWEEN DATE(?) AND DATE(?)
+		ORDER BY closed_date`
+
+	var rows *sql.Rows
+	var err error
+
+	if tx != nil {
+		rows, err = tx.Query(query, from, to)
+	} else {
+		rows, err = r.db.Query(query, from, to)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*models.ClosedDay
+	for rows.Next() {
+		var day models.ClosedDay
+		if err := rows.Scan(&day.Date, &day.Reason, &day.CreatedAt); err != nil {
+			return nil, err
+		}
+		days = append(days, &day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// AddClosedDay marks a date as closed, replacing the reason if it already is
+func (r *LibraryCalendarRepository) AddClosedDay(day *models.ClosedDay) error {
+	query := `
+		INSERT INTO library_closed_days (closed_date, reason)
+		VALUES (DATE(?), ?)
+		ON DUPLICATE KEY UPDATE reason = VALUES(reason)`
+
+	_, err := r.db.Exec(query, day.Date, day.Reason)
+	return err
+}
+
+// DeleteClosedDay reopens a previously closed date
+func (r *LibraryCalendarRepository) DeleteClosedDay(date time.Time) error {
+	_, err := r.db.Exec(`DELETE FROM library_closed_days WHERE closed_date = DATE(?)`, date)
+	return err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"library-management-system/internal/models"
+)
+
+// NotificationRepository handles database operations for notification
+// preferences and the log of notices already sent
+type NotificationRepository struct {
+	db *Database
+}
+
+// NewNotificationRepository creates a new NotificationRepository instance
+func NewNotificationRepository(db *Database) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// IsEnabled reports whether a user wants a type of notification.
+// Users who never changed a preference receive everything.
+func (r *NotificationRepository) IsEnabled(userID int64, notificationType string) (bool, error) {
+	query := `
+		SELECT enabled
+		FROM notification_preferences
+		WHERE user_id = ? AND notification_type = ?`
+
+	var enabled bool
+	err := r.db.QueryRow(query, userID, notificationType).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+// ListPreferences retrieves the preferences a user has set, keyed by type
+func (r *NotificationRepository) ListPreferences(userID int64) (map[string]*models.NotificationPreference, error) {
+	query := `
+		SELECT notification_type, enabled, updated_at
+		FROM notification_preferences
+		WHERE user_id = ?`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]*models.NotificationPreference)
+	for rows.Next() {
+		var preference models.NotificationPreference
+		var updatedAt sql.NullTime
+
+		if err := rows.Scan(&preference.Type, &preference.Enabled, &updatedAt); err != nil {
+			return nil, err
+		}
+		if updatedAt.Valid {
+			preference.UpdatedAt = &updatedAt.Time
+		}
+
+		preferences[preference.Type] = &preference
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// SetPreference turns a type of notification on or off for a user
+func (r *NotificationRepository) SetPreference(userID int64, notificationType string, enabled bool) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, notification_type, enabled)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = CURRENT_TIMESTAMP`
+
+	_, err := r.db.Exec(query, userID, notificationType, enabled)
+	return err
+}
+
+// WasSent reports whether a notice has already gone out for a reference
+func (r *NotificationRepository) WasSent(notificationType, reference string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notification_log
+		WHERE notification_type = ? AND reference = ?`
+
+	var count int
+	err := r.db.QueryRow(query, notificationType, reference).Scan(&count)
+	return count > 0, err
+}
+
+// RecordSent remembers that a notice went out so it is not sent twice
+func (r *NotificationRepository) RecordSent(userID int64, notificationType, reference string) error {
+	query := `
+		INSERT INTO notification_log (user_id, notification_type, reference)
+		VALUES (?, ?, ?)`
+
+	_, err := r.db.Exec(query, userID, notificationType, reference)
+	if err != nil && isDuplicateEntry(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// OutboxRepository handles database operations for the domain event outbox
+type OutboxRepository struct {
+	db *Database
+}
+
+// NewOutboxRepository creates a new OutboxRepository instance
+func NewOutboxRepository(db *Database) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Append writes a domain event in the caller's transaction, so the event is
+// stored if and only if the change it describes commits
+func (r *OutboxRepository) Append(tx *sql.Tx, eventType, aggregateType string, aggregateID int64, payload interface{}) error {
+	return appendEvent(tx, eventType, aggregateType, aggregateID, payload)
+}
+
+// appendEvent is shared by the repositories that emit events themselves
+func appendEvent(tx *sql.Tx, eventType, aggregateType string, aggregateID int64, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	query := `
+		INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload)
+		VALUES (?, ?, ?, ?)`
+
+	_, err = tx.Exec(query, eventType, aggregateType, aggregateID, data)
+	return err
+}
+
+// ListPending retrieves undelivered events that are due for an attempt,
+// oldest first
+func (r *OutboxRepository) ListPending(now time.Time, limit int) ([]*models.OutboxEvent, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts,
+			COALESCE(last_error, ''), next_attempt_at, created_at
+		FROM outbox_events
+		WHERE dispatched_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.OutboxEvent
+	for rows.Next() {
+		var event models.OutboxEvent
+		var payload []byte
+
+		err := rows.Scan(
+			&event.ID, &event.EventType, &event.AggregateType, &event.AggregateID,
+			&payload, &event.Attempts, &event.LastError, &event.NextAttemptAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Payload = json.RawMessage(payload)
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeliveredTo retrieves the subscribers that already handled an event
+func (r *OutboxRepository) DeliveredTo(eventID int64) (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT subscriber FROM outbox_deliveries WHERE event_id = ?`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delivered := make(map[string]bool)
+	for rows.Next() {
+		var subscriber string
+		if err := rows.Scan(&subscriber); err != nil {
+			return nil, err
+		}
+		delivered[subscriber] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return delivered, nil
+}
+
+// RecordDelivery remembers that a subscriber handled an event, so a retry
+// of the event skips it
+func (r *OutboxRepository) RecordDelivery(eventID int64, subscriber string) error {
+	query := `
+		INSERT INTO outbox_deliveries (event_id, subscriber)
+		VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, eventID, subscriber)
+	if err != nil && isDuplicateEntry(err) {
+		return nil
+	}
+
+	return err
+}
+
+// MarkDispatched records that every subscriber handled an event
+func (r *OutboxRepository) MarkDispatched(eventID int64) error {
+	query := `
+		UPDATE outbox_events
+		SET dispatched_at = CURRENT_TIMESTAMP, last_error = NULL
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, eventID)
+	return err
+}
+
+// MarkFailed records a failed attempt and when to try again
+func (r *OutboxRepository) MarkFailed(eventID int64, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = ?, next_attempt_at = ?
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, lastError, nextAttemptAt, eventID)
+	return err
+}
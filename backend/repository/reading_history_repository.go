@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// ListExpiredHistory retrieves returned loans older than cutoff whose
+// patrons do not keep their reading history, oldest first. Loans with an
+// unpaid fine are left linked until the fine is settled.
+func (r *BorrowingRepository) ListExpiredHistory(cutoff time.Time, keepByDefault bool, limit int) ([]*models.Borrowing, error) {
+	query := `
+		SELECT b.id, b.user_id, b.returned_date
+		FROM borrowings b
+		JOIN users u ON b.user_id = u.id
+		WHERE b.returned_date IS NOT NULL AND b.returned_date < ?
+		  AND COALESCE(u.keep_reading_history, ?) = FALSE
+		  AND (b.fine_amount = 0 OR b.fine_paid = TRUE)
+		ORDER BY b.returned_date, b.id
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, cutoff, keepByDefault, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var borrowings []*models.Borrowing
+	for rows.Next() {
+		var borrowing models.Borrowing
+		var returnedDate time.Time
+
+		if err := rows.Scan(&borrowing.ID, &borrowing.UserID, &returnedDate); err != nil {
+			return nil, err
+		}
+
+		borrowing.ReturnedDate = &returnedDate
+		borrowings = append(borrowings, &borrowing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return borrowings, nil
+}
+
+// UnlinkPatron removes a returned loan from its patron's reading history.
+// The loan itself stays, so circulation counts are unchanged.
+func (r *BorrowingRepository) UnlinkPatron(borrowingID, userID int64) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE borrowings
+			SET user_id = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND user_id = ? AND returned_date IS NOT NULL`,
+			borrowingID, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return models.ErrBorrowingNotFound
+		}
+
+		// Drop what ties the loan to the patron outside the borrowings row,
+		// including the loan's events and the webhook deliveries made from
+		// them. Notification references start with the borrowing ID.
+		reference := strconv.FormatInt(borrowingID, 10)
+		unlinks := []struct {
+			query string
+			args  []interface{}
+		}{
+			{`UPDATE fine_assessments SET user_id = NULL WHERE borrowing_id = ?`,
+				[]interface{}{borrowingID}},
+			{`UPDATE patron_ledger_entries SET borrowing_id = NULL, description = 'Overdue fine'
+			  WHERE borrowing_id = ? AND entry_type = 'charge'`,
+				[]interface{}{borrowingID}},
+			{`UPDATE patron_ledger_entries SET borrowing_id = NULL WHERE borrowing_id = ?`,
+				[]interface{}{borrowingID}},
+			{`UPDATE loan_renewals SET renewed_by = NULL WHERE borrowing_id = ? AND renewed_by = ?`,
+				[]interface{}{borrowingID, userID}},
+			{`DELETE FROM notification_log
+			  WHERE user_id = ? AND (reference = ? OR reference LIKE ?)`,
+				[]interface{}{userID, reference, reference + ":%"}},
+			{`UPDATE activity_logs
+			  SET old_value = JSON_REMOVE(old_value, '$.user_id', '$.user_name'),
+			      new_value = JSON_REMOVE(new_value, '$.user_id', '$.user_name'),
+			      changes = JSON_REMOVE(changes, '$.user_id', '$.user_name'),
+			      user_id = NULLIF(user_id, ?)
+			  WHERE entity_type = ? AND entity_id = ?`,
+				[]interface{}{userID, models.EntityBorrowing, borrowingID}},
+			{`UPDATE activity_logs
+			  SET old_value = JSON_REMOVE(old_value, '$.user_id'),
+			      new_value = JSON_REMOVE(new_value, '$.user_id'),
+			      changes = JSON_REMOVE(changes, '$.user_id')
+			  WHERE entity_type = ?
+			    AND entity_id IN (SELECT id FROM fine_assessments WHERE borrowing_id = ?)`,
+				[]interface{}{models.EntityFine, borrowingID}},
+			{`UPDATE webhook_deliveries
+			  SET payload = JSON_REMOVE(payload, '$.data.user_id')
+			  WHERE event_id IN (
+			      SELECT id FROM outbox_events WHERE aggregate_type = ? AND aggregate_id = ?)`,
+				[]interface{}{models.AggregateBorrowing, borrowingID}},
+			{`UPDATE outbox_events
+			  SET payload = JSON_REMOVE(payload, '$.user_id')
+			  WHERE aggregate_type = ? AND aggregate_id = ?`,
+				[]interface{}{models.AggregateBorrowing, borrowingID}},
+		}
+
+		for _, unlink := range unlinks {
+			if _, err := tx.Exec(unlink.query, unlink.args...); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// RegistrationRepository handles email verification tokens and the queue of
+// self-registered accounts waiting for staff approval
+type RegistrationRepository struct {
+	db *Database
+}
+
+// NewRegistrationRepository creates a new RegistrationRepository instance
+func NewRegistrationRepository(db *Database) *RegistrationRepository {
+	return &RegistrationRepository{db: db}
+}
+
+// CreateVerificationToken stores the hash of a new verification token for a
+// user. Links sent earlier stop working.
+func (r *RegistrationRepository) CreateVerificationToken(userID int64, tokenHash string, expiresAt time.Time) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM email_verification_tokens WHERE user_id = ? AND used_at IS NULL`, userID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+			VALUES (?, ?, ?)`,
+			userID, tokenHash, expiresAt)
+		return err
+	})
+}
+
+// ConsumeVerificationToken marks an unused, unexpired token as used and
+// returns the user it was issued to, so each link works only once
+func (r *RegistrationRepository) ConsumeVerificationToken(tx *sql.Tx, tokenHash string, now time.Time) (int64, error) {
+	var id, userID int64
+	err := tx.QueryRow(`
+		SELECT id, user_id
+		FROM email_verification_tokens
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		FOR UPDATE`, tokenHash, now).Scan(&id, &userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrInvalidVerificationToken
+		}
+		return 0, err
+	}
+
+	_, err = tx.Exec(`UPDATE email_verification_tokens SET used_at = ? WHERE id = ?`, now, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// ListExpiredUnverified returns up to limit pending accounts that never
+// verified their email, were created before the cutoff and hold no link
+// that still works
+func (r *RegistrationRepository) ListExpiredUnverified(now, cutoff time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT u.id
+		FROM users u
+		WHERE u.account_status = ? AND u.email_verified_at IS NULL AND u.created_at < ?
+		  AND NOT EXISTS (
+		      SELECT 1 FROM email_verification_tokens t
+		      WHERE t.user_id = u.id AND t.used_at IS NULL AND t.expires_at > ?)
+		ORDER BY u.id
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, models.UserStatusPending, cutoff, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ListPending retrieves verified accounts waiting for approval, oldest first
+func (r *RegistrationRepository) ListPending(page, pageSize int) ([]*models.PendingRegistration, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT id, email, full_name, COALESCE(phone, ''), COALESCE(address, ''),
+		       email_verified_at, created_at
+		FROM users
+		WHERE account_status = ? AND email_verified_at IS NOT NULL
+		ORDER BY email_verified_at, id
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, models.UserStatusPending, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var registrations []*models.PendingRegistration
+	for rows.Next() {
+		var registration models.PendingRegistration
+
+		err := rows.Scan(
+			&registration.ID, &registration.Email, &registration.FullName,
+			&registration.Phone, &registration.Address,
+			&registration.EmailVerifiedAt, &registration.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, &registration)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
+// CountPending returns the number of verified accounts waiting for approval
+func (r *RegistrationRepository) CountPending() (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM users
+		WHERE account_status = ? AND email_verified_at IS NOT NULL`
+
+	var count int
+	err := r.db.QueryRow(query, models.UserStatusPending).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// ReservationRepository handles database operations for holds
+type ReservationRepository struct {
+	db *Database
+}
+
+// NewReservationRepository creates a new ReservationRepository instance
+func NewReservationRepository(db *Database) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// reservationColumns lists the columns read by scanReservation
+const reservationColumns = `
+		r.id, r.user_id, r.book_id, r.book_copy_id, r.status, r.reservation_date,
+		r.expiry_date, r.ready_date, r.pickup_expires_at, r.fulfilled_date,
+		bk.title, u.full_name`
+
+// reservationJoins joins the book and patron names used by scanReservation
+const reservationJoins = `
+		FROM reservations r
+		JOIN books bk ON r.book_id = bk.id
+		JOIN users u ON r.user_id = u.id`
+
+// scanReservation reads a reservation in the order of reservationColumns
+func scanReservation(row rowScanner) (*models.Reservation, error) {
+	var reservation models.Reservation
+	var bookCopyID sql.NullInt64
+	var readyDate, pickupExpiresAt, fulfilledDate sql.NullTime
+
+	err := row.Scan(
+		&reservation.ID, &reservation.UserID, &reservation.BookID, &bookCopyID,
+		&reservation.Status, &reservation.ReservationDate, &reservation.ExpiryDate,
+		&readyDate, &pickupExpiresAt, &fulfilledDate,
+		&reservation.BookTitle, &reservation.UserName,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	if bookCopyID.Valid {
+		reservation.BookCopyID = &bookCopyID.Int64
+	}
+	if readyDate.Valid {
+		reservation.ReadyDate = &readyDate.Time
+	}
+	if pickupExpiresAt.Valid {
+		reservation.PickupExpiresAt = &pickupExpiresAt.Time
+	}
+	if fulfilledDate.Valid {
+		reservation.FulfilledDate = &fulfilledDate.Time
+	}
+
+	return &reservation, nil
+}
+
+// queryReservations runs a reservation query and scans every row
+func (r *ReservationRepository) queryReservations(tx *sql.Tx, query string, args ...interface{}) ([]*models.Reservation, error) {
+	var rows *sql.Rows
+	var err error
+
+	if tx != nil {
+		rows, err = tx.Query(query, args...)
+	} else {
+		rows, err = r.db.Query(query, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []*models.Reservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// GetByID retrieves a reservation by ID.
+// When tx is given the row is locked until the transaction ends.
+func (r *ReservationRepository) GetByID(tx *sql.Tx, id int64) (*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.id = ?`
+
+	if tx != nil {
+		return scanReservation(tx.QueryRow(query+" FOR UPDATE", id))
+	}
+	return scanReservation(r.db.QueryRow(query, id))
+}
+
+// Create adds a new hold to the end of a book's queue
+func (r *ReservationRepository) Create(tx *sql.Tx, reservation *models.Reservation) error {
+	query := `
+		INSERT INTO reservations (user_id, book_id, reservation_date, expiry_date, status)
+		VALUES (?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(
+		query,
+		reservation.UserID, reservation.BookID, reservation.ReservationDate,
+		reservation.ExpiryDate, reservation.Status,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	reservation.ID = id
+	return nil
+}
+
+// HasOpenHold reports whether a user already waits for a book
+func (r *ReservationRepository) HasOpenHold(tx *sql.Tx, userID, bookID int64) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM reservations
+		WHERE user_id = ? AND book_id = ? AND status IN ('pending', 'ready')`
+
+	var count int
+	err := tx.QueryRow(query, userID, bookID).Scan(&count)
+	return count > 0, err
+}
+
+// Position returns a pending hold's place in its book's queue, starting at 1
+func (r *ReservationRepository) Position(reservation *models.Reservation) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM reservations
+		WHERE book_id = ? AND status = 'pending'
+		AND (reservation_date < ? OR (reservation_date = ? AND id < ?))`
+
+	var ahead int
+	err := r.db.QueryRow(
+		query,
+		reservation.BookID, reservation.ReservationDate,
+		reservation.ReservationDate, reservation.ID,
+	).Scan(&ahead)
+
+	return ahead + 1, err
+}
+
+// NextPending locks and returns the first pending hold in a book's queue
+func (r *ReservationRepository) NextPending(tx *sql.Tx, bookID int64) (*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.book_id = ? AND r.status = 'pending' AND r.expiry_date > CURRENT_TIMESTAMP
+		ORDER BY r.reservation_date, r.id
+		LIMIT 1
+		FOR UPDATE`
+
+	return scanReservation(tx.QueryRow(query, bookID))
+}
+
+// GetReadyForCopy locks and returns the hold a copy has been set aside for
+func (r *ReservationRepository) GetReadyForCopy(tx *sql.Tx, bookCopyID int64) (*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.book_copy_id = ? AND r.status = 'ready'
+		FOR UPDATE`
+
+	return scanReservation(tx.QueryRow(query, bookCopyID))
+}
+
+// ListQueue retrieves the open holds for a book in queue order
+func (r *ReservationRepository) ListQueue(bookID int64) ([]*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.book_id = ? AND r.status IN ('pending', 'ready')
+		ORDER BY r.status = 'pending', r.reservation_date, r.id`
+
+	return r.queryReservations(nil, query, bookID)
+}
+
+// ListByUser retrieves a user's open holds
+func (r *ReservationRepository) ListByUser(userID int64) ([]*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.user_id = ? AND r.status IN ('pending', 'ready')
+		ORDER BY r.reservation_date, r.id`
+
+	return r.queryReservations(nil, query, userID)
+}
+
+// ListExpiredReady retrieves holds whose pickup deadline has passed
+func (r *ReservationRepository) ListExpiredReady(now time.Time) ([]*models.Reservation, error) {
+	query := `SELECT` + reservationColumns + reservationJoins + `
+		WHERE r.status = 'ready' AND r.pickup_expires_at < ?
+		ORDER BY r.pickup_expires_at`
+
+	return r.queryReservations(nil, query, now)
+}
+
+// ExpirePending closes pending holds that outlived their expiry date
+func (r *ReservationRepository) ExpirePending(now time.Time) (int64, error) {
+	query := `
+		UPDATE reservations
+		SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'pending' AND expiry_date < ?`
+
+	result, err := r.db.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// MarkReady sets a copy aside for a hold until the pickup deadline
+func (r *ReservationRepository) MarkReady(tx *sql.Tx, id, bookCopyID int64, readyDate, pickupExpiresAt time.Time) error {
+	query := `
+		UPDATE reservations
+		SET status = 'ready', book_copy_id = ?, ready_date = ?, pickup_expires_at = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := tx.Exec(query, bookCopyID, readyDate, pickupExpiresAt, id)
+	return err
+}
+
+// UpdateStatus closes a hold as fulfilled, cancelled or expired
+func (r *ReservationRepository) UpdateStatus(tx *sql.Tx, id int64, status string) error {
+	query := `
+		UPDATE reservations
+		SET status = ?,
+			fulfilled_date = IF(? = 'fulfilled', CURRENT_TIMESTAMP, fulfilled_date),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := tx.Exec(query, status, status, id)
+	return err
+}
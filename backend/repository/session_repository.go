@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"library-management-system/internal/models"
+)
+
+// SessionRepository handles database operations for user sessions
+type SessionRepository struct {
+	db *Database
+}
+
+// NewSessionRepository creates a new SessionRepository instance
+func NewSessionRepository(db *Database) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// scanSession reads a session row selected in the column order of GetByID
+func scanSession(row rowScanner) (*models.Session, error) {
+	var session models.Session
+	var ipAddress, userAgent sql.NullString
+	var lastUsedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.Device, &ipAddress, &userAgent,
+		&session.CreatedAt, &lastUsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	session.IPAddress = ipAddress.String
+	session.UserAgent = userAgent.String
+	if lastUsedAt.Valid {
+		session.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &session, nil
+}
+
+// Create starts a new session
+func (r *SessionRepository) Create(session *models.Session) error {
+	query := `
+		INSERT INTO user_sessions (user_id, device, ip_address, user_agent, last_used_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	result, err := r.db.Exec(query, session.UserID, session.Device, session.IPAddress, session.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	session.ID = id
+	return nil
+}
+
+// AttachTokens links stored tokens to the session they were issued for
+func (r *SessionRepository) AttachTokens(sessionID int64, tokenHashes ...string) error {
+	if len(tokenHashes) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tokenHashes)), ", ")
+	query := `UPDATE access_tokens SET session_id = ? WHERE token_hash IN (` + placeholders + `)`
+
+	args := make([]interface{}, 0, len(tokenHashes)+1)
+	args = append(args, sessionID)
+	for _, hash := range tokenHashes {
+		args = append(args, hash)
+	}
+
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// GetByID retrieves a session by ID, whether or not it is still active
+func (r *SessionRepository) GetByID(id int64) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, device, ip_address, user_agent, created_at, last_used_at
+		FROM user_sessions
+		WHERE id = ?`
+
+	return scanSession(r.db.QueryRow(query, id))
+}
+
+// GetIDByTokenHash returns the session a stored token belongs to, or 0 for
+// tokens issued before sessions were tracked
+func (r *SessionRepository) GetIDByTokenHash(tokenHash string) (int64, error) {
+	query := `SELECT session_id FROM access_tokens WHERE token_hash = ?`
+
+	var sessionID sql.NullInt64
+	err := r.db.QueryRow(query, tokenHash).Scan(&sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrSessionNotFound
+		}
+		return 0, err
+	}
+
+	return sessionID.Int64, nil
+}
+
+// TouchByTokenHash records that the session of a token was just used. Writes
+// are skipped when the session was already touched within the last minute.
+func (r *SessionRepository) TouchByTokenHash(tokenHash string) error {
+	query := `
+		UPDATE user_sessions s
+		JOIN access_tokens t ON t.session_id = s.id
+		SET s.last_used_at = CURRENT_TIMESTAMP
+		WHERE t.token_hash = ?
+		  AND (s.last_used_at IS NULL OR s.last_used_at < CURRENT_TIMESTAMP - INTERVAL 1 MINUTE)`
+
+	_, err := r.db.Exec(query, tokenHash)
+	return err
+}
+
+// RotateToken marks a refresh token as replaced. The row is kept so a later
+// attempt to use it again can be detected. It reports false when the token
+// had already been rotated, so two refreshes racing with the same token
+// cannot both succeed.
+func (r *SessionRepository) RotateToken(tokenHash string) (bool, error) {
+	query := `
+		UPDATE access_tokens
+		SET revoked = TRUE, rotated_at = CURRENT_TIMESTAMP
+		WHERE token_hash = ? AND rotated_at IS NULL`
+
+	result, err := r.db.Exec(query, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ListActive retrieves a user's sessions that still hold an unexpired token,
+// most recently used first
+func (r *SessionRepository) ListActive(userID int64) ([]*models.Session, error) {
+	query := `
+		SELECT s.id, s.user_id, s.device, s.ip_address, s.user_agent, s.created_at, s.last_used_at
+		FROM user_sessions s
+		WHERE s.user_id = ? AND s.revoked_at IS NULL
+		  AND EXISTS (
+			SELECT 1 FROM access_tokens t
+			WHERE t.session_id = s.id AND t.revoked = FALSE AND t.expires_at > CURRENT_TIMESTAMP
+		  )
+		ORDER BY COALESCE(s.last_used_at, s.created_at) DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Revoke ends one of a user's sessions and deletes its tokens so they stop
+// working immediately
+func (r *SessionRepository) Revoke(userID, sessionID int64, reason string) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE user_sessions
+			SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = ?
+			WHERE id = ? AND user_id = ? AND revoked_at IS NULL`,
+			reason, sessionID, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return models.ErrSessionNotFound
+		}
+
+		_, err = tx.Exec(`DELETE FROM access_tokens WHERE session_id = ?`, sessionID)
+		return err
+	})
+}
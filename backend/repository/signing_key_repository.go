@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// SigningKeyRepository handles database operations for JWT signing keys
+type SigningKeyRepository struct {
+	db *Database
+}
+
+// NewSigningKeyRepository creates a new SigningKeyRepository instance
+func NewSigningKeyRepository(db *Database) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// Create stores a new signing key
+func (r *SigningKeyRepository) Create(key *models.SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (id, algorithm, private_key, public_key, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, key.ID, key.Algorithm, key.PrivateKey, key.PublicKey, key.CreatedAt)
+	return err
+}
+
+// ListUsable retrieves the keys that may still verify tokens, newest first
+func (r *SigningKeyRepository) ListUsable(now time.Time) ([]*models.SigningKey, error) {
+	query := `
+		SELECT id, algorithm, private_key, public_key, created_at, retired_at, expires_at
+		FROM signing_keys
+		WHERE expires_at IS NULL OR expires_at > ?
+		ORDER BY created_at DESC, id`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*models.SigningKey
+	for rows.Next() {
+		var key models.SigningKey
+		var retiredAt, expiresAt sql.NullTime
+
+		err := rows.Scan(
+			&key.ID, &key.Algorithm, &key.PrivateKey, &key.PublicKey,
+			&key.CreatedAt, &retiredAt, &expiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		if expiresAt.Valid {
+			key.ExpiresAt = &expiresAt.Time
+		}
+
+		keys = append(keys, &key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FirstCreatedAt returns when the oldest signing key was created, or false
+// when there are no keys yet
+func (r *SigningKeyRepository) FirstCreatedAt() (time.Time, bool, error) {
+	var createdAt sql.NullTime
+	err := r.db.QueryRow(`SELECT MIN(created_at) FROM signing_keys`).Scan(&createdAt)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return createdAt.Time, createdAt.Valid, nil
+}
+
+// RetireOthers stops every key except the given one from signing. Retired
+// keys keep verifying until expiresAt.
+func (r *SigningKeyRepository) RetireOthers(keepID string, retiredAt, expiresAt time.Time) error {
+	query := `
+		UPDATE signing_keys
+		SET retired_at = ?, expires_at = ?
+		WHERE id <> ? AND retired_at IS NULL`
+
+	_, err := r.db.Exec(query, retiredAt, expiresAt, keepID)
+	return err
+}
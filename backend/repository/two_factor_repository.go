@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+)
+
+// TwoFactorRepository handles database operations for TOTP replay protection
+// and recovery codes
+type TwoFactorRepository struct {
+	db *Database
+}
+
+// NewTwoFactorRepository creates a new TwoFactorRepository instance
+func NewTwoFactorRepository(db *Database) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// ClaimStep records a TOTP time step as used. It reports false when the
+// step, or a later one, was already accepted for the user.
+func (r *TwoFactorRepository) ClaimStep(userID, step int64) (bool, error) {
+	query := `
+		UPDATE users
+		SET two_factor_last_step = ?
+		WHERE id = ? AND (two_factor_last_step IS NULL OR two_factor_last_step < ?)`
+
+	result, err := r.db.Exec(query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// ResetSteps forgets the last accepted step, used when a new secret is set
+func (r *TwoFactorRepository) ResetSteps(tx *sql.Tx, userID int64) error {
+	query := `UPDATE users SET two_factor_last_step = NULL WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = r.db.Exec(query, userID)
+	}
+	return err
+}
+
+// ReplaceRecoveryCodes discards a user's recovery codes and stores new hashes
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(tx *sql.Tx, userID int64, codeHashes []string) error {
+	if tx == nil {
+		return r.db.Transaction(func(tx *sql.Tx) error {
+			return r.ReplaceRecoveryCodes(tx, userID, codeHashes)
+		})
+	}
+
+	if err := r.DeleteRecoveryCodes(tx, userID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO two_factor_recovery_codes (user_id, code_hash) VALUES (?, ?)`
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(query, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes removes every recovery code of a user
+func (r *TwoFactorRepository) DeleteRecoveryCodes(tx *sql.Tx, userID int64) error {
+	query := `DELETE FROM two_factor_recovery_codes WHERE user_id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = r.db.Exec(query, userID)
+	}
+	return err
+}
+
+// UseRecoveryCode marks an unused recovery code as spent. It reports false
+// when the code does not exist or was already used.
+func (r *TwoFactorRepository) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	query := `
+		UPDATE two_factor_recovery_codes
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes a user has left
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM two_factor_recovery_codes
+		WHERE user_id = ? AND used_at IS NULL`
+
+	var count int
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
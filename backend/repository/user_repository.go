@@ -73,18 +73,25 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 }
 
 // Create adds a new user to the database
-func (r *UserRepository) Create(user *models.User) error {
+func (r *UserRepository) Create(tx *sql.Tx, user *models.User) error {
 	query := `
 		INSERT INTO users (
 			email, password_hash, full_name, role, phone, address, 
 			account_status, two_factor_enabled
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.Exec(
-		query,
+	args := []interface{}{
 		user.Email, user.PasswordHash, user.FullName, user.Role,
 		user.Phone, user.Address, user.AccountStatus, user.TwoFactorEnabled,
-	)
+	}
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = r.db.Exec(query, args...)
+	}
 	if err != nil {
 		return err
 	}
@@ -99,18 +106,24 @@ func (r *UserRepository) Create(user *models.User) error {
 }
 
 // Update updates an existing user
-func (r *UserRepository) Update(user *models.User) error {
+func (r *UserRepository) Update(tx *sql.Tx, user *models.User) error {
 	query := `
 		UPDATE users
 		SET email = ?, full_name = ?, role = ?, phone = ?, address = ?,
 			account_status = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?`
 
-	_, err := r.db.Exec(
-		query,
+	args := []interface{}{
 		user.Email, user.FullName, user.Role, user.Phone,
 		user.Address, user.AccountStatus, user.ID,
-	)
+	}
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, args...)
+	} else {
+		_, err = r.db.Exec(query, args...)
+	}
 	return err
 }
 
@@ -125,6 +138,52 @@ func (r *UserRepository) UpdatePassword(userID int64, passwordHash string) error
 	return err
 }
 
+// UpdateRole changes a user's role
+func (r *UserRepository) UpdateRole(tx *sql.Tx, userID int64, role models.UserRole) error {
+	query := `
+		UPDATE users
+		SET role = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, role, userID)
+	} else {
+		_, err = r.db.Exec(query, role, userID)
+	}
+	return err
+}
+
+// UpdateStatus changes a user's account status. Suspensions record a
+// user.suspended event in the same transaction.
+func (r *UserRepository) UpdateStatus(tx *sql.Tx, userID int64, status models.UserStatus) error {
+	query := `
+		UPDATE users
+		SET account_status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	result, err := tx.Exec(query, status, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	if status != models.UserStatusSuspended {
+		return nil
+	}
+
+	return appendEvent(tx, models.EventUserSuspended, models.AggregateUser, userID, map[string]interface{}{
+		"user_id": userID,
+	})
+}
+
 // UpdateLoginAttempts updates a user's failed login attempts
 func (r *UserRepository) UpdateLoginAttempts(userID int64, attempts int) error {
 	query := `
@@ -136,6 +195,61 @@ func (r *UserRepository) UpdateLoginAttempts(userID int64, attempts int) error {
 	return err
 }
 
+// GetLockoutStatus retrieves a user's failed login state. Inside a
+// transaction the row stays locked until it ends.
+func (r *UserRepository) GetLockoutStatus(tx *sql.Tx, userID int64) (*models.LockoutStatus, error) {
+	query := `SELECT failed_login_attempts, lockout_count, locked_until FROM users WHERE id = ?`
+
+	var row *sql.Row
+	if tx != nil {
+		row = tx.QueryRow(query+` FOR UPDATE`, userID)
+	} else {
+		row = r.db.QueryRow(query, userID)
+	}
+
+	var status models.LockoutStatus
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&status.FailedAttempts, &status.LockoutCount, &lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		status.LockedUntil = &lockedUntil.Time
+	}
+
+	return &status, nil
+}
+
+// SaveLockoutStatus stores a user's failed login state
+func (r *UserRepository) SaveLockoutStatus(tx *sql.Tx, userID int64, status *models.LockoutStatus) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = ?, lockout_count = ?, locked_until = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, status.FailedAttempts, status.LockoutCount, status.LockedUntil, userID)
+	} else {
+		_, err = r.db.Exec(query, status.FailedAttempts, status.LockoutCount, status.LockedUntil, userID)
+	}
+	return err
+}
+
+// ResetLockout clears a user's failed attempts and any lockout
+func (r *UserRepository) ResetLockout(userID int64) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, lockout_count = 0, locked_until = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
 // UpdateLastLogin updates a user's last login timestamp
 func (r *UserRepository) UpdateLastLogin(userID int64) error {
 	query := `
@@ -148,13 +262,171 @@ func (r *UserRepository) UpdateLastLogin(userID int64) error {
 	return err
 }
 
-// Delete removes a user from the database
-func (r *UserRepository) Delete(id int64) error {
-	query := `DELETE FROM users WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+// LockForUpdate locks a user's row until the transaction ends so that
+// concurrent circulation requests for the same user are serialized
+func (r *UserRepository) LockForUpdate(tx *sql.Tx, userID int64) error {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM users WHERE id = ? FOR UPDATE`, userID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// closedAccountScrubs remove the personal data a closed account leaves
+// outside the users table, including the copies of events kept for
+// dispatch and webhook deliveries. Loans, fines and ledger entries are kept
+// for circulation statistics.
+var closedAccountScrubs = []string{
+	`DELETE FROM access_tokens WHERE user_id = ?`,
+	`UPDATE user_sessions
+	 SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP),
+	     revoked_reason = COALESCE(revoked_reason, 'account_closed'),
+	     ip_address = NULL, user_agent = NULL, device = ''
+	 WHERE user_id = ?`,
+	`DELETE FROM two_factor_recovery_codes WHERE user_id = ?`,
+	`DELETE FROM user_identities WHERE user_id = ?`,
+	`DELETE FROM notification_preferences WHERE user_id = ?`,
+	`DELETE FROM email_verification_tokens WHERE user_id = ?`,
+	`UPDATE auth_logs SET ip_address = NULL, user_agent = NULL WHERE user_id = ?`,
+	`UPDATE activity_logs SET ip_address = NULL WHERE user_id = ?`,
+	`UPDATE activity_logs SET old_value = NULL, new_value = NULL, changes = NULL
+	 WHERE entity_type = 'user' AND entity_id = ?`,
+	`UPDATE outbox_events SET payload = JSON_REMOVE(payload, '$.user_id')
+	 WHERE JSON_EXTRACT(payload, '$.user_id') = ?`,
+	`UPDATE webhook_deliveries SET payload = JSON_REMOVE(payload, '$.data.user_id')
+	 WHERE JSON_EXTRACT(payload, '$.data.user_id') = ?`,
+}
+
+// Anonymize closes an account in place of deleting it. The row is kept so
+// loans and fines still refer to it, but its personal fields are replaced,
+// its credentials removed and its sessions revoked. It returns
+// ErrAccountClosed when the account was already closed.
+func (r *UserRepository) Anonymize(tx *sql.Tx, id int64) error {
+	query := `
+		UPDATE users
+		SET email = CONCAT('closed-', id, '@closed.invalid'), full_name = 'Closed account',
+			phone = '', address = '', password_hash = '',
+			reset_token = NULL, reset_token_expires = NULL,
+			two_factor_secret = NULL, two_factor_enabled = FALSE, two_factor_last_step = NULL,
+			account_status = ?, closed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND closed_at IS NULL`
+
+	result, err := tx.Exec(query, models.UserStatusDeactivated, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrAccountClosed
+	}
+
+	for _, scrub := range closedAccountScrubs {
+		if _, err := tx.Exec(scrub, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PhoneInUse reports whether an open account has the phone number, compared
+// on its digits alone so formatting differences still match
+func (r *UserRepository) PhoneInUse(digits string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM users
+		WHERE closed_at IS NULL AND REGEXP_REPLACE(phone, '[^0-9]', '') = ?`
+
+	var count int
+	err := r.db.QueryRow(query, digits).Scan(&count)
+	return count > 0, err
+}
+
+// IsEmailVerified reports whether a user confirmed their email address
+func (r *UserRepository) IsEmailVerified(tx *sql.Tx, userID int64) (bool, error) {
+	query := `SELECT email_verified_at IS NOT NULL FROM users WHERE id = ?`
+
+	var verified bool
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, userID).Scan(&verified)
+	} else {
+		err = r.db.QueryRow(query, userID).Scan(&verified)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, models.ErrUserNotFound
+	}
+	return verified, err
+}
+
+// MarkEmailVerified records that a user confirmed their email address
+func (r *UserRepository) MarkEmailVerified(tx *sql.Tx, userID int64) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = r.db.Exec(query, userID)
+	}
 	return err
 }
 
+// GetReadingHistoryPreference returns whether a user keeps their reading
+// history, or nil when they follow the library default
+func (r *UserRepository) GetReadingHistoryPreference(userID int64) (*bool, error) {
+	var keep sql.NullBool
+	err := r.db.QueryRow(`SELECT keep_reading_history FROM users WHERE id = ?`, userID).Scan(&keep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if !keep.Valid {
+		return nil, nil
+	}
+	return &keep.Bool, nil
+}
+
+// SetReadingHistoryPreference stores whether a user keeps their reading
+// history. A nil keep returns them to the library default.
+func (r *UserRepository) SetReadingHistoryPreference(userID int64, keep *bool) error {
+	query := `
+		UPDATE users
+		SET keep_reading_history = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	result, err := r.db.Exec(query, keep, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
 // List retrieves users with pagination
 func (r *UserRepository) List(page, pageSize int) ([]*models.User, error) {
 	// Safeguard against pagination parameter manipulation
@@ -276,6 +548,18 @@ func (r *UserRepository) SetTwoFactorSecret(userID int64, secret string, enabled
 	return err
 }
 
+// ClearTwoFactorSecret removes a user's two-factor secret and disables 2FA
+func (r *UserRepository) ClearTwoFactorSecret(userID int64) error {
+	query := `
+		UPDATE users
+		SET two_factor_secret = NULL, two_factor_enabled = FALSE, two_factor_last_step = NULL,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
 // GetTwoFactorSecret retrieves a user's two-factor authentication secret
 func (r *UserRepository) GetTwoFactorSecret(userID int64) (string, error) {
 	query := `SELECT two_factor_secret FROM users WHERE id = ?`
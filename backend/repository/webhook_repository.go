@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// WebhookRepository handles database operations for webhooks and their deliveries
+type WebhookRepository struct {
+	db *Database
+}
+
+// NewWebhookRepository creates a new WebhookRepository instance
+func NewWebhookRepository(db *Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookColumns lists the columns read by scanWebhook
+const webhookColumns = `
+		id, url, secret, event_types, active, consecutive_failures, disabled_at,
+		created_by, created_at, updated_at`
+
+// scanWebhook reads a webhook in the order of webhookColumns
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var eventTypes []byte
+	var disabledAt sql.NullTime
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&webhook.ID, &webhook.URL, &webhook.Secret, &eventTypes, &webhook.Active,
+		&webhook.ConsecutiveFailures, &disabledAt, &createdBy,
+		&webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(eventTypes, &webhook.EventTypes); err != nil {
+		return nil, err
+	}
+	if disabledAt.Valid {
+		webhook.DisabledAt = &disabledAt.Time
+	}
+	if createdBy.Valid {
+		webhook.CreatedBy = &createdBy.Int64
+	}
+
+	return &webhook, nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(id int64) (*models.Webhook, error) {
+	query := `SELECT` + webhookColumns + `
+		FROM webhooks
+		WHERE id = ?`
+
+	return scanWebhook(r.db.QueryRow(query, id))
+}
+
+// List retrieves every webhook
+func (r *WebhookRepository) List() ([]*models.Webhook, error) {
+	return r.queryWebhooks(`SELECT` + webhookColumns + `
+		FROM webhooks
+		ORDER BY id`)
+}
+
+// ListActive retrieves the webhooks that currently receive events
+func (r *WebhookRepository) ListActive() ([]*models.Webhook, error) {
+	return r.queryWebhooks(`SELECT` + webhookColumns + `
+		FROM webhooks
+		WHERE active = TRUE
+		ORDER BY id`)
+}
+
+// queryWebhooks runs a webhook query and scans every row
+func (r *WebhookRepository) queryWebhooks(query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Create adds a new webhook
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	eventTypes, err := json.Marshal(webhook.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhooks (url, secret, event_types, active, created_by)
+		VALUES (?, ?, ?, ?, ?)`
+
+	result, err := r.db.Exec(query, webhook.URL, webhook.Secret, eventTypes, webhook.Active, webhook.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = id
+	return nil
+}
+
+// Update changes a webhook's address, event filter and active flag.
+// Re-enabling a webhook clears its failure streak.
+func (r *WebhookRepository) Update(webhook *models.Webhook) error {
+	eventTypes, err := json.Marshal(webhook.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhooks
+		SET url = ?, event_types = ?,
+			consecutive_failures = IF(? AND NOT active, 0, consecutive_failures),
+			disabled_at = IF(?, NULL, disabled_at),
+			active = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	result, err := r.db.Exec(
+		query,
+		webhook.URL, eventTypes, webhook.Active, webhook.Active, webhook.Active, webhook.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a webhook and its delivery log
+func (r *WebhookRepository) Delete(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RecordSuccess clears a webhook's failure streak
+func (r *WebhookRepository) RecordSuccess(id int64) error {
+	query := `
+		UPDATE webhooks
+		SET consecutive_failures = 0
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// RecordFailure extends a webhook's failure streak and disables it once the
+// streak reaches disableAfter. It reports whether the webhook was disabled.
+func (r *WebhookRepository) RecordFailure(id int64, disableAfter int) (bool, error) {
+	var disabled bool
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		var failures int
+		var active bool
+
+		query := `SELECT consecutive_failures, active FROM webhooks WHERE id = ? FOR UPDATE`
+		if err := tx.QueryRow(query, id).Scan(&failures, &active); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrWebhookNotFound
+			}
+			return err
+		}
+
+		failures++
+		disabled = active && failures >= disableAfter
+
+		updateQuery := `
+			UPDATE webhooks
+			SET consecutive_failures = ?,
+				active = IF(?, FALSE, active),
+				disabled_at = IF(?, CURRENT_TIMESTAMP, disabled_at)
+			WHERE id = ?`
+
+		_, err := tx.Exec(updateQuery, failures, disabled, disabled, id)
+		return err
+	})
+
+	return disabled, err
+}
+
+// deliveryColumns lists the columns read by scanDelivery
+const deliveryColumns = `
+		id, webhook_id, event_id, event_type, payload, status, attempts,
+		response_status, last_error, duration_ms, next_attempt_at, delivered_at,
+		created_at, updated_at`
+
+// scanDelivery reads a delivery in the order of deliveryColumns
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	var durationMs sql.NullInt64
+	var nextAttemptAt, deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType,
+		&delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&responseStatus, &lastError, &durationMs, &nextAttemptAt, &deliveredAt,
+		&delivery.CreatedAt, &delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if lastError.Valid {
+		delivery.LastError = lastError.String
+	}
+	if durationMs.Valid {
+		delivery.DurationMs = &durationMs.Int64
+	}
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
+
+// EnqueueDelivery schedules an event for delivery to a webhook. Enqueueing
+// the same event twice is a no-op, so redelivered outbox events are safe.
+func (r *WebhookRepository) EnqueueDelivery(webhookID int64, event *models.OutboxEvent, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		VALUES (?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, webhookID, event.ID, event.EventType, payload)
+	if err != nil && isDuplicateEntry(err) {
+		return nil
+	}
+
+	return err
+}
+
+// ListDueDeliveries retrieves pending deliveries to active webhooks that
+// are due for an attempt, oldest first
+func (r *WebhookRepository) ListDueDeliveries(now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status,
+			d.attempts, d.response_status, d.last_error, d.duration_ms,
+			d.next_attempt_at, d.delivered_at, d.created_at, d.updated_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON d.webhook_id = w.id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= ? AND w.active = TRUE
+		ORDER BY d.id
+		LIMIT ?`
+
+	return r.queryDeliveries(query, now, limit)
+}
+
+// ListDeliveries retrieves a webhook's delivery log, newest first
+func (r *WebhookRepository) ListDeliveries(webhookID int64, page, pageSize int) ([]*models.WebhookDelivery, error) {
+	// Safeguard against pagination parameter manipulation
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	query := `SELECT` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`
+
+	return r.queryDeliveries(query, webhookID, pageSize, offset)
+}
+
+// CountDeliveries counts a webhook's deliveries
+func (r *WebhookRepository) CountDeliveries(webhookID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = ?`, webhookID).Scan(&count)
+	return count, err
+}
+
+// queryDeliveries runs a delivery query and scans every row
+func (r *WebhookRepository) queryDeliveries(query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = ?, last_error = ?,
+			duration_ms = ?, next_attempt_at = ?, delivered_at = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`
+
+	var lastError sql.NullString
+	if delivery.LastError != "" {
+		lastError = sql.NullString{String: delivery.LastError, Valid: true}
+	}
+
+	_, err := r.db.Exec(
+		query,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, lastError,
+		delivery.DurationMs, delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID,
+	)
+	return err
+}
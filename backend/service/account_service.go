@@ -0,0 +1,307 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// Activity log actions for patron accounts
+const (
+	activityFinePayment = "fine_payment"
+	activityFineWaiver  = "fine_waiver"
+)
+
+// AccountService handles patron account balances, payments and waivers
+type AccountService struct {
+	db            *repository.Database
+	ledgerRepo    *repository.LedgerRepository
+	borrowingRepo *repository.BorrowingRepository
+	userRepo      *repository.UserRepository
+	activityRepo  *repository.ActivityLogRepository
+}
+
+// NewAccountService creates a new AccountService instance
+func NewAccountService(
+	db *repository.Database,
+	ledgerRepo *repository.LedgerRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	userRepo *repository.UserRepository,
+	activityRepo *repository.ActivityLogRepository,
+) *AccountService {
+	return &AccountService{
+		db:            db,
+		ledgerRepo:    ledgerRepo,
+		borrowingRepo: borrowingRepo,
+		userRepo:      userRepo,
+		activityRepo:  activityRepo,
+	}
+}
+
+// GetBalance returns the summary of a patron's account
+func (s *AccountService) GetBalance(userID int64) (*models.AccountBalance, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	return s.ledgerRepo.Balance(nil, userID)
+}
+
+// ListEntries retrieves a page of a patron's ledger and the total number of entries
+func (s *AccountService) ListEntries(userID int64, page, pageSize int) ([]*models.LedgerEntry, int, error) {
+	entries, err := s.ledgerRepo.ListByUser(userID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+
+	total, err := s.ledgerRepo.CountByUser(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// RecordPayment takes a payment from a patron and issues a receipt.
+// When borrowingID is set the payment is applied to that borrowing's fine.
+func (s *AccountService) RecordPayment(userID int64, amount float64, method string, borrowingID *int64, actor models.Actor) (*models.Receipt, error) {
+	staffID := actor.UserID
+
+	amount = roundCents(amount)
+	if amount <= 0 {
+		return nil, models.ErrInvalidAmount
+	}
+
+	method = strings.TrimSpace(method)
+	if method == "" {
+		method = "cash"
+	}
+
+	if _, err := requirePermission(s.userRepo, staffID, models.PermPaymentsRecord); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBorrowingOwner(userID, borrowingID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.LedgerEntry{
+		UserID:        userID,
+		BorrowingID:   borrowingID,
+		EntryType:     models.LedgerEntryPayment,
+		Amount:        amount,
+		Description:   "Fine payment",
+		PaymentMethod: method,
+		StaffID:       &staffID,
+	}
+
+	issuedAt := time.Now()
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.applyCredit(tx, entry, actor); err != nil {
+			return err
+		}
+
+		entry.ReceiptNumber = fmt.Sprintf("R%s-%06d", issuedAt.Format("20060102"), entry.ID)
+		if err := s.ledgerRepo.SetReceiptNumber(tx, entry.ID, entry.ReceiptNumber); err != nil {
+			return err
+		}
+
+		return recordChange(s.activityRepo, tx, actor, activityFinePayment, models.EntityLedgerEntry, entry.ID, nil, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Receipt{
+		Number:        entry.ReceiptNumber,
+		UserID:        user.ID,
+		UserName:      user.FullName,
+		BorrowingID:   borrowingID,
+		Amount:        amount,
+		PaymentMethod: method,
+		StaffID:       &staffID,
+		IssuedAt:      issuedAt,
+	}, nil
+}
+
+// WaiveFine forgives some or all of a patron's outstanding fines
+func (s *AccountService) WaiveFine(userID int64, amount float64, reason string, borrowingID *int64, actor models.Actor) (*models.LedgerEntry, error) {
+	staffID := actor.UserID
+
+	amount = roundCents(amount)
+	if amount <= 0 {
+		return nil, models.ErrInvalidAmount
+	}
+
+	if _, err := requirePermission(s.userRepo, staffID, models.PermFinesWaive); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBorrowingOwner(userID, borrowingID); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "Fine waived"
+	}
+
+	entry := &models.LedgerEntry{
+		UserID:      userID,
+		BorrowingID: borrowingID,
+		EntryType:   models.LedgerEntryWaiver,
+		Amount:      amount,
+		Description: reason,
+		StaffID:     &staffID,
+	}
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.applyCredit(tx, entry, actor); err != nil {
+			return err
+		}
+
+		return recordChange(s.activityRepo, tx, actor, activityFineWaiver, models.EntityLedgerEntry, entry.ID, nil, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetReceipt retrieves a previously issued receipt
+func (s *AccountService) GetReceipt(receiptNumber string) (*models.Receipt, error) {
+	entry, err := s.ledgerRepo.GetByReceiptNumber(strings.TrimSpace(receiptNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(entry.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Receipt{
+		Number:        entry.ReceiptNumber,
+		UserID:        user.ID,
+		UserName:      user.FullName,
+		BorrowingID:   entry.BorrowingID,
+		Amount:        entry.Amount,
+		PaymentMethod: entry.PaymentMethod,
+		StaffID:       entry.StaffID,
+		IssuedAt:      entry.CreatedAt,
+	}, nil
+}
+
+// applyCredit writes a payment or waiver and marks fines that it settles as paid
+func (s *AccountService) applyCredit(tx *sql.Tx, entry *models.LedgerEntry, actor models.Actor) error {
+	// Serialize account changes for this patron
+	if err := s.userRepo.LockForUpdate(tx, entry.UserID); err != nil {
+		return err
+	}
+
+	account, err := s.ledgerRepo.Balance(tx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if entry.Amount > roundCents(account.Balance) {
+		return models.ErrAmountExceedsBalance
+	}
+
+	if entry.BorrowingID != nil {
+		owed, err := s.ledgerRepo.BorrowingBalance(tx, *entry.BorrowingID)
+		if err != nil {
+			return err
+		}
+		if entry.Amount > roundCents(owed) {
+			return models.ErrAmountExceedsBalance
+		}
+	}
+
+	if err := s.ledgerRepo.CreateEntry(tx, entry); err != nil {
+		return err
+	}
+
+	// Keep the fine_paid flags on borrowings in step with the ledger
+	if roundCents(account.Balance-entry.Amount) <= 0 {
+		borrowingIDs, err := s.borrowingRepo.ListUnpaidFines(tx, entry.UserID)
+		if err != nil {
+			return err
+		}
+		for _, borrowingID := range borrowingIDs {
+			if err := s.markFinePaid(tx, borrowingID, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if entry.BorrowingID != nil {
+		owed, err := s.ledgerRepo.BorrowingBalance(tx, *entry.BorrowingID)
+		if err != nil {
+			return err
+		}
+		if roundCents(owed) <= 0 {
+			return s.markFinePaid(tx, *entry.BorrowingID, actor)
+		}
+	}
+
+	return nil
+}
+
+// markFinePaid flags the fine on a borrowing as settled and records the
+// change in the audit trail
+func (s *AccountService) markFinePaid(tx *sql.Tx, borrowingID int64, actor models.Actor) error {
+	borrowing, err := s.borrowingRepo.Reload(tx, borrowingID)
+	if err != nil {
+		return err
+	}
+	if borrowing.FinePaid || borrowing.FineAmount <= 0 {
+		return nil
+	}
+
+	if err := s.borrowingRepo.MarkFinePaid(tx, borrowingID); err != nil {
+		return err
+	}
+
+	paid := *borrowing
+	paid.FinePaid = true
+	return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBorrowing, borrowingID, borrowing, &paid)
+}
+
+// checkBorrowingOwner makes sure a payment or waiver targets the patron's own borrowing
+func (s *AccountService) checkBorrowingOwner(userID int64, borrowingID *int64) error {
+	if borrowingID == nil {
+		return nil
+	}
+
+	borrowing, err := s.borrowingRepo.GetByID(*borrowingID)
+	if err != nil {
+		return err
+	}
+
+	if borrowing.UserID != userID {
+		return models.ErrBorrowingNotFound
+	}
+
+	return nil
+}
+
+// roundCents rounds an amount of money to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"library-management-system/internal/models"
+)
+
+// auditIgnoredFields change on every write and would only add noise to diffs
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// activityRecorder stores audit trail entries. The activity log repository
+// implements it.
+type activityRecorder interface {
+	Create(tx *sql.Tx, log *models.ActivityLog) error
+}
+
+// recordChange writes an audit trail entry for a change to an entity, in the
+// same transaction as the change. before is nil for creations and after is
+// nil for deletions; both are stored as their JSON form, so fields hidden
+// from JSON never reach the trail. Updates that change nothing are skipped.
+func recordChange(
+	activityRepo activityRecorder,
+	tx *sql.Tx,
+	actor models.Actor,
+	action, entityType string,
+	entityID int64,
+	before, after interface{},
+) error {
+	oldFields, oldValue, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	newFields, newValue, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	changes := diffFields(oldFields, newFields)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	err = activityRepo.Create(tx, &models.ActivityLog{
+		UserID:     actorUserID(actor),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		OldValue:   oldValue,
+		NewValue:   newValue,
+		Changes:    changesJSON,
+		IPAddress:  actor.IPAddress,
+		RequestID:  actor.RequestID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record audit trail: %w", err)
+	}
+
+	return nil
+}
+
+// actorUserID returns the acting user's ID for an activity log, or nil for
+// background jobs
+func actorUserID(actor models.Actor) *int64 {
+	if actor.UserID == 0 {
+		return nil
+	}
+	id := actor.UserID
+	return &id
+}
+
+// auditSnapshot returns an entity's JSON form, both decoded into fields and
+// as text
+func auditSnapshot(entity interface{}) (map[string]interface{}, string, error) {
+	if entity == nil {
+		return nil, "", nil
+	}
+
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, "", err
+	}
+
+	return fields, string(raw), nil
+}
+
+// diffFields lists the fields whose values differ between two snapshots
+func diffFields(before, after map[string]interface{}) map[string]models.FieldChange {
+	changes := make(map[string]models.FieldChange)
+
+	for name, oldValue := range before {
+		if auditIgnoredFields[name] {
+			continue
+		}
+		if newValue, ok := after[name]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[name] = models.FieldChange{Old: oldValue, New: after[name]}
+		}
+	}
+
+	for name, newValue := range after {
+		if auditIgnoredFields[name] {
+			continue
+		}
+		if _, ok := before[name]; !ok {
+			changes[name] = models.FieldChange{Old: nil, New: newValue}
+		}
+	}
+
+	return changes
+}
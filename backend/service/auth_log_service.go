@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// AuthLogService lets admins search and export the security audit log and
+// scan it for suspicious activity
+type AuthLogService struct {
+	authLogRepo *repository.AuthLogRepository
+	userRepo    *repository.UserRepository
+	config      config.AuditConfig
+}
+
+// NewAuthLogService creates a new AuthLogService instance
+func NewAuthLogService(
+	authLogRepo *repository.AuthLogRepository,
+	userRepo *repository.UserRepository,
+	cfg config.AuditConfig,
+) *AuthLogService {
+	return &AuthLogService{
+		authLogRepo: authLogRepo,
+		userRepo:    userRepo,
+		config:      cfg,
+	}
+}
+
+// Search returns a page of auth logs matching the filters and the total
+// number of matches
+func (s *AuthLogService) Search(actorID int64, filters models.AuthLogFilters, page, pageSize int) ([]*models.AuthLog, int, error) {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermAuditLogsRead); err != nil {
+		return nil, 0, err
+	}
+	if err := validateAuthLogFilters(filters); err != nil {
+		return nil, 0, err
+	}
+
+	logs, err := s.authLogRepo.Search(filters, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search auth logs: %w", err)
+	}
+
+	total, err := s.authLogRepo.CountMatching(filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count auth logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// Export writes the auth logs matching the filters to w as CSV or NDJSON,
+// oldest first. Nothing is written when the request is rejected.
+func (s *AuthLogService) Export(actorID int64, filters models.AuthLogFilters, format string, w io.Writer) error {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermAuditLogsRead); err != nil {
+		return err
+	}
+	if err := validateAuthLogFilters(filters); err != nil {
+		return err
+	}
+
+	switch format {
+	case models.AuthLogExportCSV:
+		return s.exportCSV(filters, w)
+	case models.AuthLogExportNDJSON:
+		return s.exportNDJSON(filters, w)
+	}
+
+	return models.ErrInvalidExportFormat
+}
+
+// Anomalies reports addresses with bursts of failed logins and logins from
+// user agents a user has not used before. Without a start time the
+// configured window is scanned.
+func (s *AuthLogService) Anomalies(actorID int64, since time.Time) ([]*models.AuthLogAnomaly, error) {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermAuditLogsRead); err != nil {
+		return nil, err
+	}
+
+	if since.IsZero() {
+		since = time.Now().Add(-time.Duration(s.config.AnomalyWindowHours) * time.Hour)
+	}
+
+	bursts, err := s.authLogRepo.LoginFailureBursts(since, s.config.FailureBurstThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find failed login bursts: %w", err)
+	}
+
+	newAgents, err := s.authLogRepo.NewUserAgentLogins(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find logins from new user agents: %w", err)
+	}
+
+	anomalies := make([]*models.AuthLogAnomaly, 0, len(bursts)+len(newAgents))
+	anomalies = append(anomalies, bursts...)
+	anomalies = append(anomalies, newAgents...)
+
+	return anomalies, nil
+}
+
+// exportCSV writes matching auth logs as CSV with a header row
+func (s *AuthLogService) exportCSV(filters models.AuthLogFilters, w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		"id", "user_id", "action", "status", "severity",
+		"ip_address", "user_agent", "details", "created_at",
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.authLogRepo.EachMatching(filters, s.config.ExportMaxRows, func(log *models.AuthLog) error {
+		userID := ""
+		if log.UserID != 0 {
+			userID = strconv.FormatInt(log.UserID, 10)
+		}
+
+		return writer.Write([]string{
+			strconv.FormatInt(log.ID, 10),
+			userID,
+			csvSafe(string(log.Action)),
+			csvSafe(string(log.Status)),
+			csvSafe(string(log.Severity)),
+			csvSafe(log.IPAddress),
+			csvSafe(log.UserAgent),
+			csvSafe(log.Details),
+			log.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export auth logs: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportNDJSON writes matching auth logs as one JSON object per line
+func (s *AuthLogService) exportNDJSON(filters models.AuthLogFilters, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	err := s.authLogRepo.EachMatching(filters, s.config.ExportMaxRows, func(log *models.AuthLog) error {
+		return encoder.Encode(log)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export auth logs: %w", err)
+	}
+
+	return nil
+}
+
+// validateAuthLogFilters rejects time ranges that end before they start
+func validateAuthLogFilters(filters models.AuthLogFilters) error {
+	if !filters.From.IsZero() && !filters.To.IsZero() && !filters.To.After(filters.From) {
+		return models.ErrInvalidAuthLogFilter
+	}
+	return nil
+}
+
+// csvSafe stops spreadsheet programs from evaluating user supplied values,
+// such as user agents, as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -1,605 +1,900 @@
 package service
 
-func (s *AuthService) RefreshToken(refreshToken, ipAddress, userAgent string) (*models.TokenPair, *models.User, error) {
-	// Verify that the token is a refresh token
-	if token.Claims.(jwt.MapClaims)["type"] != "refresh" {
-	return nil, nil, models.ErrInvalidToken
-}
-
-// Get token hash
-tokenHash := token.Claims.(jwt.MapClaims)["jti"].(string)
-
-// Check if token exists in the database
-storedToken, err := s.tokenRepo.GetByHash(tokenHash)
-if err != nil || storedToken == nil {
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetLifetime is how long an emailed reset link stays valid
+const passwordResetLifetime = 24 * time.Hour
+
+// AuthService handles authentication, tokens and account security
+type AuthService struct {
+	db                  *repository.Database
+	userRepo            *repository.UserRepository
+	authLogRepo         *repository.AuthLogRepository
+	tokenRepo           *repository.TokenRepository
+	sessionRepo         *repository.SessionRepository
+	activityRepo        *repository.ActivityLogRepository
+	sessionService      *SessionService
+	notificationService *NotificationService
+	twoFactorService    *TwoFactorService
+	lockoutService      *LockoutService
+	keyManager          *KeyManager
+	logger              *logger.Logger
+	config              config.AuthConfig
+}
+
+// NewAuthService creates a new AuthService instance
+func NewAuthService(
+	db *repository.Database,
+	userRepo *repository.UserRepository,
+	authLogRepo *repository.AuthLogRepository,
+	tokenRepo *repository.TokenRepository,
+	sessionRepo *repository.SessionRepository,
+	activityRepo *repository.ActivityLogRepository,
+	sessionService *SessionService,
+	notificationService *NotificationService,
+	twoFactorService *TwoFactorService,
+	lockoutService *LockoutService,
+	keyManager *KeyManager,
+	logger *logger.Logger,
+	config config.AuthConfig,
+) *AuthService {
+	return &AuthService{
+		db:                  db,
+		userRepo:            userRepo,
+		authLogRepo:         authLogRepo,
+		tokenRepo:           tokenRepo,
+		sessionRepo:         sessionRepo,
+		activityRepo:        activityRepo,
+		sessionService:      sessionService,
+		notificationService: notificationService,
+		twoFactorService:    twoFactorService,
+		lockoutService:      lockoutService,
+		keyManager:          keyManager,
+		logger:              logger,
+		config:              config,
+	}
+}
+
+// Login checks a user's password. Users with two-factor authentication get
+// a short-lived MFA token instead of access tokens, to be exchanged through
+// CompleteTwoFactorLogin. Repeated failures lock the account and throttle
+// the client address.
+func (s *AuthService) Login(email, password, ipAddress, userAgent string) (*models.LoginResult, error) {
+	if err := s.lockoutService.CheckIP(ipAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		s.lockoutService.RecordIPFailure(ipAddress)
+		s.authLogRepo.Create(&models.AuthLog{
+			Action:    models.AuthActionLogin,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Unknown email",
+		})
+		return nil, models.ErrInvalidCredentials
+	}
+
+	if err := s.lockoutService.CheckUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    user.ID,
+			Action:    models.AuthActionLogin,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Wrong password",
+		})
+		s.lockoutService.RecordIPFailure(ipAddress)
+		if err := s.lockoutService.RecordFailure(user.ID, ipAddress, userAgent); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrInvalidCredentials
+	}
+
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+
+	if user.TwoFactorEnabled {
+		mfaToken, expiresAt, err := s.generateMFAToken(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate MFA token: %w", err)
+		}
+
+		return &models.LoginResult{
+			User:         user,
+			MFARequired:  true,
+			MFAToken:     mfaToken,
+			MFAExpiresAt: &expiresAt,
+		}, nil
+	}
+
+	return s.completeLogin(user, "Password", ipAddress, userAgent)
+}
+
+// CompleteTwoFactorLogin finishes a login started with a password by checking
+// a code from the user's authenticator app or a recovery code
+func (s *AuthService) CompleteTwoFactorLogin(mfaToken, code, ipAddress, userAgent string) (*models.LoginResult, error) {
+	if err := s.lockoutService.CheckIP(ipAddress); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.parseToken(mfaToken, "mfa_pending")
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	userID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil {
+		return nil, models.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, models.ErrInvalidToken
+	}
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+	if err := s.lockoutService.CheckUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorService.Verify(user.ID, code); err != nil {
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    user.ID,
+			Action:    models.AuthActionLogin,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Wrong two-factor code",
+		})
+		s.lockoutService.RecordIPFailure(ipAddress)
+		if err := s.lockoutService.RecordFailure(user.ID, ipAddress, userAgent); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(user, "Password and two-factor code", ipAddress, userAgent)
+}
+
+// LoginExternal signs in a user who was authenticated by an external
+// identity provider. Locked accounts stay locked, and users with two-factor
+// authentication still need a code.
+func (s *AuthService) LoginExternal(user *models.User, method, ipAddress, userAgent string) (*models.LoginResult, error) {
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+	if err := s.lockoutService.CheckUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		mfaToken, expiresAt, err := s.generateMFAToken(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate MFA token: %w", err)
+		}
+
+		return &models.LoginResult{
+			User:         user,
+			MFARequired:  true,
+			MFAToken:     mfaToken,
+			MFAExpiresAt: &expiresAt,
+		}, nil
+	}
+
+	return s.completeLogin(user, method, ipAddress, userAgent)
+}
+
+// completeLogin issues tokens once every login step has passed
+func (s *AuthService) completeLogin(user *models.User, method, ipAddress, userAgent string) (*models.LoginResult, error) {
+	tokenPair, err := s.issueTokens(user, 0, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lockoutService.RecordSuccess(user.ID)
+	s.userRepo.UpdateLastLogin(user.ID)
 	s.authLogRepo.Create(&models.AuthLog{
-		Action:    models.AuthActionRefreshToken,
+		UserID:    user.ID,
+		Action:    models.AuthActionLogin,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
-		Status:    models.StatusFailure,
-		Details:   "Token not found in database",
+		Status:    models.StatusSuccess,
+		Details:   method,
 	})
-	return nil, nil, models.ErrInvalidToken
+
+	return &models.LoginResult{User: user, Tokens: tokenPair}, nil
 }
 
-// Check if token is expired
-if storedToken.ExpiresAt.Before(time.Now()) {
+// RefreshToken exchanges a refresh token for a new token pair in the same
+// session. A refresh token that was already rotated ends its whole family.
+func (s *AuthService) RefreshToken(refreshToken, ipAddress, userAgent string) (*models.TokenPair, *models.User, error) {
+	claims, err := s.parseToken(refreshToken, "refresh")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenHash, ok := claims["jti"].(string)
+	if !ok {
+		return nil, nil, models.ErrInvalidToken
+	}
+
+	// Check if token exists in the database
+	storedToken, err := s.tokenRepo.GetByHash(tokenHash)
+	if err != nil || storedToken == nil {
+		s.authLogRepo.Create(&models.AuthLog{
+			Action:    models.AuthActionRefreshToken,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Token not found in database",
+		})
+		return nil, nil, models.ErrInvalidToken
+	}
+
+	// Check if token is expired
+	if storedToken.ExpiresAt.Before(time.Now()) {
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    storedToken.UserID,
+			Action:    models.AuthActionRefreshToken,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Token expired",
+		})
+		return nil, nil, models.ErrTokenExpired
+	}
+
+	// Check if token type is refresh
+	if storedToken.TokenType != models.TokenTypeRefresh {
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    storedToken.UserID,
+			Action:    models.AuthActionRefreshToken,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Not a refresh token",
+		})
+		return nil, nil, models.ErrInvalidToken
+	}
+
+	// Keep the old refresh token as rotated so it can be recognised if it is
+	// presented again. Losing the race to rotate it means it was copied, so
+	// end its whole family.
+	sessionID, _ := s.sessionRepo.GetIDByTokenHash(tokenHash)
+	rotated, err := s.sessionRepo.RotateToken(tokenHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !rotated {
+		if err := s.sessionService.RevokeForTokenReuse(storedToken.UserID, sessionID, ipAddress, userAgent); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, models.ErrRefreshTokenReused
+	}
+
+	// Get user from database
+	user, err := s.userRepo.GetByID(storedToken.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Check account status
+	if user.AccountStatus != models.UserStatusActive {
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    user.ID,
+			Action:    models.AuthActionRefreshToken,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   fmt.Sprintf("Account status: %s", user.AccountStatus),
+		})
+		return nil, nil, models.ErrAccountNotActive
+	}
+
+	// Generate and store a new token pair in the same family
+	tokenPair, err := s.issueTokens(user, sessionID, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Log successful token refresh
 	s.authLogRepo.Create(&models.AuthLog{
-		UserID:    storedToken.UserID,
+		UserID:    user.ID,
 		Action:    models.AuthActionRefreshToken,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
-		Status:    models.StatusFailure,
-		Details:   "Token expired",
+		Status:    models.StatusSuccess,
 	})
-	return nil, nil, models.ErrTokenExpired
+
+	return tokenPair, user, nil
 }
 
-// Check if token type is refresh
-if storedToken.TokenType != models.TokenTypeRefresh {
+// Logout invalidates a user's tokens
+func (s *AuthService) Logout(accessToken, ipAddress, userAgent string) error {
+	// Parse and validate access token
+	claims, err := s.parseToken(accessToken, "access")
+	if err != nil {
+		return err
+	}
+
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return models.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil {
+		return models.ErrInvalidToken
+	}
+
+	tokenHash, ok := claims["jti"].(string)
+	if !ok {
+		return models.ErrInvalidToken
+	}
+
+	// Find and delete the access token, ending its session along with it
+	storedToken, err := s.tokenRepo.GetByHash(tokenHash)
+	if err == nil && storedToken != nil {
+		if sessionID, err := s.sessionRepo.GetIDByTokenHash(tokenHash); err == nil && sessionID != 0 {
+			s.sessionRepo.Revoke(storedToken.UserID, sessionID, models.SessionRevokedLogout)
+		}
+		s.tokenRepo.Delete(storedToken.ID)
+	}
+
+	// Log the logout
 	s.authLogRepo.Create(&models.AuthLog{
-		UserID:    storedToken.UserID,
-		Action:    models.AuthActionRefreshToken,
+		UserID:    userID,
+		Action:    models.AuthActionLogout,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
-		Status:    models.StatusFailure,
-		Details:   "Not a refresh token",
+		Status:    models.StatusSuccess,
 	})
-	return nil, nil, models.ErrInvalidToken
-}
 
-// Get user from database
-user, err := s.userRepo.GetByID(storedToken.UserID)
-if err != nil {
-	return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	return nil
 }
 
-// Check account status
-if user.AccountStatus != models.UserStatusActive {
+// LogoutAllSessions invalidates all tokens for a specific user
+func (s *AuthService) LogoutAllSessions(userID int64, ipAddress, userAgent string) error {
+	// Delete all tokens for this user
+	err := s.tokenRepo.DeleteAllForUser(userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user tokens: %w", err)
+	}
+
+	// Log the action
 	s.authLogRepo.Create(&models.AuthLog{
-		UserID:    user.ID,
-		Action:    models.AuthActionRefreshToken,
+		UserID:    userID,
+		Action:    models.AuthActionLogoutAll,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
-		Status:    models.StatusFailure,
-		Details:   fmt.Sprintf("Account status: %s", user.AccountStatus),
+		Status:    models.StatusSuccess,
 	})
-	return nil, nil, models.ErrAccountNotActive
-}
-
-// Revoke the old refresh token
-s.tokenRepo.Delete(storedToken.ID)
-
-// Generate new token pair
-tokenPair, err := s.generateTokenPair(user)
-if err != nil {
-	return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
-}
-
-// Store new tokens
-accessTokenExpires := time.Now().Add(time.Duration(s.config.AccessTokenLifetimeMinutes) * time.Minute)
-refreshTokenExpires := time.Now().Add(time.Duration(s.config.RefreshTokenLifetimeDays) * 24 * time.Hour)
-
-err = s.tokenRepo.Create(&models.AccessToken{
-	UserID:    user.ID,
-	TokenHash: tokenPair.AccessTokenHash,
-	TokenType: models.TokenTypeAccess,
-	ExpiresAt: accessTokenExpires,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-})
-if err != nil {
-	return nil, nil, fmt.Errorf("failed to store access token: %w", err)
-}
-
-err = s.tokenRepo.Create(&models.AccessToken{
-	UserID:    user.ID,
-	TokenHash: tokenPair.RefreshTokenHash,
-	TokenType: models.TokenTypeRefresh,
-	ExpiresAt: refreshTokenExpires,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-})
-if err != nil {
-	return nil, nil, fmt.Errorf("failed to store refresh token: %w", err)
-}
-
-// Log successful token refresh
-s.authLogRepo.Create(&models.AuthLog{
-	UserID:    user.ID,
-	Action:    models.AuthActionRefreshToken,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-	Status:    models.StatusSuccess,
-})
-
-return tokenPair, user, nil
-}
-
-return nil, nil, fmt.Errorf("invalid token"){
-}
-
-// Logout invalidates a user's tokens
-func (s *AuthService) Logout(accessToken, ipAddress, userAgent string) error {
-// Parse and validate access token
-token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-}
-return []byte(s.config.JWTSecret), nil
-})
-
-if err != nil || !token.Valid {
-return models.ErrInvalidToken
-}
-
-claims, ok := token.Claims.(jwt.MapClaims)
-if !ok {
-return models.ErrInvalidToken
-}
-
-userID, ok := claims["sub"].(string)
-if !ok {
-return models.ErrInvalidToken
-}
-
-tokenHash, ok := claims["jti"].(string)
-if !ok {
-return models.ErrInvalidToken
-}
 
-// Find and delete the access token
-storedToken, err := s.tokenRepo.GetByHash(tokenHash)
-if err == nil && storedToken != nil {
-s.tokenRepo.Delete(storedToken.ID)
-}
-
-// Log the logout
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    userID,
-Action:    models.AuthActionLogout,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
-
-return nil
-}
-
-// LogoutAllSessions invalidates all tokens for a specific user
-func (s *AuthService) LogoutAllSessions(userID, ipAddress, userAgent string) error {
-// Delete all tokens for this user
-err := s.tokenRepo.DeleteAllForUser(userID)
-if err != nil {
-return fmt.Errorf("failed to delete user tokens: %w", err)
-}
-
-// Log the action
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    userID,
-Action:    models.AuthActionLogoutAll,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
-
-return nil
+	return nil
 }
 
 // ChangePassword updates a user's password
-func (s *AuthService) ChangePassword(userID, currentPassword, newPassword string, ipAddress, userAgent string) error {
-// Get user
-user, err := s.userRepo.GetByID(userID)
-if err != nil {
-return models.ErrUserNotFound
-}
-
-// Verify current password
-err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword))
-if err != nil {
-// Log failed password change attempt
-s.authLogRepo.Create(&models.AuthLog{
-	UserID:    userID,
-	Action:    models.AuthActionChangePassword,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-	Status:    models.StatusFailure,
-	Details:   "Current password verification failed",
-})
-return models.ErrInvalidCredentials
-}
-
-// Hash new password
-hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-if err != nil {
-return fmt.Errorf("failed to hash password: %w", err)
-}
-
-// Update password in database
-user.PasswordHash = string(hashedPassword)
-err = s.userRepo.UpdatePassword(userID, string(hashedPassword))
-if err != nil {
-return fmt.Errorf("failed to update password: %w", err)
-}
-
-// Log successful password change
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    userID,
-Action:    models.AuthActionChangePassword,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
-
-// Invalidate all existing sessions to force re-login with new password
-return s.LogoutAllSessions(userID, ipAddress, userAgent)
-}
-
-// RequestPasswordReset initiates a password reset process
-func (s *AuthService) RequestPasswordReset(email, ipAddress, userAgent string) (string, error) {
-// Get user by email
-user, err := s.userRepo.GetByEmail(email)
-if err != nil {
-// We don't want to leak information about whether an email exists
-// But we still log the attempt
-s.authLogRepo.Create(&models.AuthLog{
-	Action:    models.AuthActionPasswordResetRequest,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-	Status:    models.StatusFailure,
-	Details:   "Email not found",
-})
-return "", nil
-}
-
-// Generate reset token
-tokenBytes := make([]byte, 32)
-_, err = rand.Read(tokenBytes)
-if err != nil {
-return "", fmt.Errorf("failed to generate reset token: %w", err)
-}
-resetToken := base64.URLEncoding.EncodeToString(tokenBytes)
-
-// Hash token for storage
-hashedToken, err := bcrypt.GenerateFromPassword([]byte(resetToken), bcrypt.DefaultCost)
-if err != nil {
-return "", fmt.Errorf("failed to hash reset token: %w", err)
-}
-
-// Store reset token with expiry (usually 24 hours)
-expiry := time.Now().Add(24 * time.Hour)
-err = s.userRepo.StorePasswordResetToken(user.ID, string(hashedToken), expiry)
-if err != nil {
-return "", fmt.Errorf("failed to store reset token: %w", err)
-}
+func (s *AuthService) ChangePassword(userID int64, currentPassword, newPassword, ipAddress, userAgent string) error {
+	// Get user
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	// Verify current password
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword))
+	if err != nil {
+		// Log failed password change attempt
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    userID,
+			Action:    models.AuthActionChangePassword,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Current password verification failed",
+		})
+		return models.ErrInvalidCredentials
+	}
+
+	// Hash new password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Update password in database
+	user.PasswordHash = string(hashedPassword)
+	err = s.userRepo.UpdatePassword(userID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// Log successful password change
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    userID,
+		Action:    models.AuthActionChangePassword,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
 
-// Log the request
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    user.ID,
-Action:    models.AuthActionPasswordResetRequest,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
+	// Invalidate all existing sessions to force re-login with new password
+	return s.LogoutAllSessions(userID, ipAddress, userAgent)
+}
+
+// RequestPasswordReset initiates a password reset process. The reset link is
+// emailed to the user; the token is never returned to the caller, and the
+// response is the same whether or not the email is known.
+func (s *AuthService) RequestPasswordReset(email, ipAddress, userAgent string) error {
+	// Get user by email
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		// We don't want to leak information about whether an email exists
+		// But we still log the attempt
+		s.authLogRepo.Create(&models.AuthLog{
+			Action:    models.AuthActionPasswordResetRequest,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Email not found",
+		})
+		return nil
+	}
+
+	// Generate reset token
+	tokenBytes := make([]byte, 32)
+	_, err = rand.Read(tokenBytes)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	resetToken := base64.URLEncoding.EncodeToString(tokenBytes)
+
+	// Store only the token's hash, with its expiry
+	expiry := time.Now().Add(passwordResetLifetime)
+	err = s.userRepo.SetResetToken(user.ID, hashResetToken(resetToken), expiry)
+	if err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	// Email the reset link. A failed send is not reported to the caller, so
+	// the response does not reveal that the email exists.
+	if err := s.notificationService.SendPasswordReset(user, resetToken, passwordResetLifetime); err != nil {
+		s.logger.Error("Failed to send password reset email", "user_id", user.ID, "error", err)
+		s.authLogRepo.Create(&models.AuthLog{
+			UserID:    user.ID,
+			Action:    models.AuthActionPasswordResetRequest,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Reset email could not be sent",
+		})
+		return nil
+	}
+
+	// Log the request
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionPasswordResetRequest,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
 
-return resetToken, nil
+	return nil
 }
 
 // ResetPassword completes the password reset process
 func (s *AuthService) ResetPassword(email, resetToken, newPassword, ipAddress, userAgent string) error {
-// Get user by email
-user, err := s.userRepo.GetByEmail(email)
-if err != nil {
-return models.ErrUserNotFound
-}
-
-// Get stored reset token
-storedToken, expiry, err := s.userRepo.GetPasswordResetToken(user.ID)
-if err != nil {
-return models.ErrInvalidToken
-}
-
-// Check if token is expired
-if expiry.Before(time.Now()) {
-s.authLogRepo.Create(&models.AuthLog{
-	UserID:    user.ID,
-	Action:    models.AuthActionPasswordReset,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-	Status:    models.StatusFailure,
-	Details:   "Reset token expired",
-})
-return models.ErrTokenExpired
-}
-
-// Verify token
-err = bcrypt.CompareHashAndPassword([]byte(storedToken), []byte(resetToken))
-if err != nil {
-s.authLogRepo.Create(&models.AuthLog{
-	UserID:    user.ID,
-	Action:    models.AuthActionPasswordReset,
-	IPAddress: ipAddress,
-	UserAgent: userAgent,
-	Status:    models.StatusFailure,
-	Details:   "Invalid reset token",
-})
-return models.ErrInvalidToken
-}
-
-// Hash new password
-hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-if err != nil {
-return fmt.Errorf("failed to hash password: %w", err)
-}
-
-// Update password
-err = s.userRepo.UpdatePassword(user.ID, string(hashedPassword))
-if err != nil {
-return fmt.Errorf("failed to update password: %w", err)
-}
-
-// Clear reset token
-err = s.userRepo.ClearPasswordResetToken(user.ID)
-if err != nil {
-return fmt.Errorf("failed to clear reset token: %w", err)
-}
-
-// Invalidate all existing sessions
-err = s.tokenRepo.DeleteAllForUser(user.ID)
-if err != nil {
-return fmt.Errorf("failed to invalidate sessions: %w", err)
-}
-
-// Log successful password reset
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    user.ID,
-Action:    models.AuthActionPasswordReset,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
-
-return nil
-}
-
-// ToggleTwoFactor enables or disables 2FA for a user
-func (s *AuthService) ToggleTwoFactor(userID string, enable bool, ipAddress, userAgent string) error {
-// Update user's 2FA status
-err := s.userRepo.UpdateTwoFactorStatus(userID, enable)
-if err != nil {
-return fmt.Errorf("failed to update 2FA status: %w", err)
-}
-
-// Log the action
-action := models.AuthActionEnable2FA
-if !enable {
-action = models.AuthActionDisable2FA
-}
-
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    userID,
-Action:    action,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-})
+	// Find the user holding this unexpired token
+	user, err := s.userRepo.GetByResetToken(hashResetToken(resetToken))
+	if err != nil || !strings.EqualFold(user.Email, strings.TrimSpace(email)) {
+		if err != nil && !errors.Is(err, models.ErrInvalidResetToken) {
+			return fmt.Errorf("failed to look up reset token: %w", err)
+		}
+
+		s.authLogRepo.Create(&models.AuthLog{
+			Action:    models.AuthActionPasswordReset,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Details:   "Invalid or expired reset token",
+		})
+		return models.ErrInvalidToken
+	}
+
+	// Hash new password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Update password
+	err = s.userRepo.UpdatePassword(user.ID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// Clear reset token
+	err = s.userRepo.ClearResetToken(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clear reset token: %w", err)
+	}
+
+	// Invalidate all existing sessions
+	err = s.tokenRepo.DeleteAllForUser(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate sessions: %w", err)
+	}
+
+	// Log successful password reset
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionPasswordReset,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
 
-return nil
+	return nil
 }
 
-// ValidateToken checks if a token is valid
+// ValidateToken checks that an access token is signed, unexpired and not
+// revoked, and that its user may still sign in
 func (s *AuthService) ValidateToken(tokenString string) (*models.User, error) {
-// Parse and validate token
-token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-}
-return []byte(s.config.JWTSecret), nil
-})
-
-if err != nil || !token.Valid {
-return nil, models.ErrInvalidToken
-}
-
-claims, ok := token.Claims.(jwt.MapClaims)
-if !ok {
-return nil, models.ErrInvalidToken
-}
-
-// Check token type
-tokenType, ok := claims["type"].(string)
-if !ok || tokenType != "access" {
-return nil, models.ErrInvalidToken
-}
-
-// Get user ID from token
-userID, ok := claims["sub"].(string)
-if !ok {
-return nil, models.ErrInvalidToken
-}
-
-// Check if token exists in database
-tokenHash, ok := claims["jti"].(string)
-if !ok {
-return nil, models.ErrInvalidToken
-}
-
-storedToken, err := s.tokenRepo.GetByHash(tokenHash)
-if err != nil || storedToken == nil {
-return nil, models.ErrInvalidToken
-}
-
-// Check if token is expired (in database)
-if storedToken.ExpiresAt.Before(time.Now()) {
-return nil, models.ErrTokenExpired
-}
-
-// Get user
-user, err := s.userRepo.GetByID(userID)
-if err != nil {
-return nil, models.ErrUserNotFound
-}
-
-// Check account status
-if user.AccountStatus != models.UserStatusActive {
-return nil, models.ErrAccountNotActive
-}
-
-return user, nil
-}
-
-// UpdateUserRole updates a user's role
-func (s *AuthService) UpdateUserRole(userID string, newRole models.UserRole, adminID, ipAddress, userAgent string) error {
-// Check if admin exists
-admin, err := s.userRepo.GetByID(adminID)
-if err != nil {
-return models.ErrUserNotFound
-}
-
-// Check admin permissions
-if admin.Role != models.UserRoleAdmin && admin.Role != models.UserRoleSuperAdmin {
-return models.ErrUnauthorized
-}
+	claims, err := s.parseToken(tokenString, "access")
+	if err != nil {
+		return nil, err
+	}
+
+	// Get user ID from token
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return nil, models.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil {
+		return nil, models.ErrInvalidToken
+	}
+
+	// Check if token exists in database
+	tokenHash, ok := claims["jti"].(string)
+	if !ok {
+		return nil, models.ErrInvalidToken
+	}
+
+	storedToken, err := s.tokenRepo.GetByHash(tokenHash)
+	if err != nil || storedToken == nil {
+		return nil, models.ErrInvalidToken
+	}
+
+	// Check if token is expired (in database)
+	if storedToken.ExpiresAt.Before(time.Now()) {
+		return nil, models.ErrTokenExpired
+	}
+
+	// Record activity for the session list
+	s.sessionRepo.TouchByTokenHash(tokenHash)
+
+	// Get user
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	// Check account status
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+
+	return user, nil
+}
+
+// UpdateUserRole updates a user's role. The actor is the admin making the change.
+func (s *AuthService) UpdateUserRole(userID int64, newRole models.UserRole, actor models.Actor, userAgent string) error {
+	adminID := actor.UserID
+
+	// Check if admin exists
+	admin, err := s.userRepo.GetByID(adminID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	// Check admin permissions
+	if !models.HasPermission(admin.Role, models.PermUsersManage) {
+		return models.ErrUnauthorized
+	}
+
+	// Get user
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	// Prevent role escalation by anyone who may not manage admins
+	if models.HasPermission(newRole, models.PermUsersManage) && !models.HasPermission(admin.Role, models.PermAdminsManage) {
+		return models.ErrUnauthorized
+	}
+
+	// Update user role
+	updated := *user
+	updated.Role = newRole
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.UpdateRole(tx, userID, newRole); err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityUser, userID, user, &updated)
+	})
+	if err != nil {
+		return err
+	}
 
-// Get user
-user, err := s.userRepo.GetByID(userID)
-if err != nil {
-return models.ErrUserNotFound
-}
+	// Log the change
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    adminID,
+		Action:    models.AuthActionUpdateRole,
+		IPAddress: actor.IPAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+		Details:   fmt.Sprintf("Updated user %d role to %s", userID, newRole),
+	})
 
-// Prevent role escalation to admin by non-superadmins
-if (newRole == models.UserRoleAdmin || newRole == models.UserRoleSuperAdmin) && admin.Role != models.UserRoleSuperAdmin {
-return models.ErrUnauthorized
-}
+	return nil
+}
+
+// UpdateAccountStatus changes a user's account status. The actor is the
+// admin making the change.
+func (s *AuthService) UpdateAccountStatus(userID int64, status models.UserStatus, actor models.Actor, userAgent string) error {
+	adminID := actor.UserID
+
+	// Check if admin exists
+	admin, err := s.userRepo.GetByID(adminID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	// Check admin permissions
+	if !models.HasPermission(admin.Role, models.PermUsersManage) {
+		return models.ErrUnauthorized
+	}
+
+	// Get user
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	// Prevent modifying admin accounts by anyone who may not manage admins
+	if models.HasPermission(user.Role, models.PermAdminsManage) && !models.HasPermission(admin.Role, models.PermAdminsManage) {
+		return models.ErrUnauthorized
+	}
+
+	// Update account status
+	updated := *user
+	updated.AccountStatus = status
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.UpdateStatus(tx, userID, status); err != nil {
+			return fmt.Errorf("failed to update account status: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityUser, userID, user, &updated)
+	})
+	if err != nil {
+		return err
+	}
 
-// Update user role
-user.Role = newRole
-err = s.userRepo.UpdateRole(userID, newRole)
-if err != nil {
-return fmt.Errorf("failed to update role: %w", err)
-}
+	// Log the change
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    adminID,
+		Action:    models.AuthActionUpdateStatus,
+		IPAddress: actor.IPAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+		Details:   fmt.Sprintf("Updated user %d status to %s", userID, status),
+	})
 
-// Log the change
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    adminID,
-Action:    models.AuthActionUpdateRole,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-Details:   fmt.Sprintf("Updated user %s role to %s", userID, newRole),
-})
-
-return nil
-}
+	// If account is suspended or deactivated, invalidate all sessions
+	if status == models.UserStatusSuspended || status == models.UserStatusDeactivated {
+		s.tokenRepo.DeleteAllForUser(userID)
+	}
+
+	return nil
+}
+
+// parseToken verifies a token's signature and expiry and returns its claims
+// when it is of the expected type
+func (s *AuthService) parseToken(tokenString, tokenType string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, s.keyManager.Keyfunc, jwt.WithValidMethods(s.keyManager.ValidMethods()))
+
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, models.ErrTokenExpired
+	}
+	if err != nil || !token.Valid {
+		return nil, models.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, models.ErrInvalidToken
+	}
+
+	if claimType, _ := claims["type"].(string); claimType != tokenType {
+		return nil, models.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// issueTokens generates a new token pair for a user and stores both tokens
+// so they can be revoked. A sessionID of 0 starts a new session.
+func (s *AuthService) issueTokens(user *models.User, sessionID int64, ipAddress, userAgent string) (*models.TokenPair, error) {
+	if sessionID == 0 {
+		session := &models.Session{
+			UserID:    user.ID,
+			Device:    describeDevice(userAgent),
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+		}
+		if err := s.sessionRepo.Create(session); err != nil {
+			return nil, fmt.Errorf("failed to start session: %w", err)
+		}
+		sessionID = session.ID
+	}
+
+	tokenPair, err := s.generateTokenPair(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	accessTokenExpires := time.Now().Add(time.Duration(s.config.AccessTokenLifetimeMinutes) * time.Minute)
+	refreshTokenExpires := time.Now().Add(time.Duration(s.config.RefreshTokenLifetimeDays) * 24 * time.Hour)
+
+	err = s.tokenRepo.Create(&models.AccessToken{
+		UserID:    user.ID,
+		TokenHash: tokenPair.AccessTokenHash,
+		TokenType: models.TokenTypeAccess,
+		ExpiresAt: accessTokenExpires,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store access token: %w", err)
+	}
 
-// UpdateAccountStatus changes a user's account status
-func (s *AuthService) UpdateAccountStatus(userID string, status models.UserStatus, adminID, ipAddress, userAgent string) error {
-// Check if admin exists
-admin, err := s.userRepo.GetByID(adminID)
-if err != nil {
-return models.ErrUserNotFound
-}
+	err = s.tokenRepo.Create(&models.AccessToken{
+		UserID:    user.ID,
+		TokenHash: tokenPair.RefreshTokenHash,
+		TokenType: models.TokenTypeRefresh,
+		ExpiresAt: refreshTokenExpires,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
 
-// Check admin permissions
-if admin.Role != models.UserRoleAdmin && admin.Role != models.UserRoleSuperAdmin {
-return models.ErrUnauthorized
-}
+	if err := s.sessionRepo.AttachTokens(sessionID, tokenPair.AccessTokenHash, tokenPair.RefreshTokenHash); err != nil {
+		return nil, fmt.Errorf("failed to attach tokens to session: %w", err)
+	}
 
-// Get user
-user, err := s.userRepo.GetByID(userID)
-if err != nil {
-return models.ErrUserNotFound
+	return tokenPair, nil
 }
 
-// Prevent modifying super admin accounts by non-super admins
-if user.Role == models.UserRoleSuperAdmin && admin.Role != models.UserRoleSuperAdmin {
-return models.ErrUnauthorized
-}
+// generateMFAToken signs the token that proves a user's password was
+// accepted while their two-factor code is still outstanding
+func (s *AuthService) generateMFAToken(user *models.User) (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.twoFactorService.mfaTokenLifetime())
 
-// Update account status
-user.AccountStatus = status
-err = s.userRepo.UpdateStatus(userID, status)
-if err != nil {
-return fmt.Errorf("failed to update account status: %w", err)
-}
+	claims := jwt.MapClaims{
+		"sub":  strconv.FormatInt(user.ID, 10),
+		"exp":  expiresAt.Unix(),
+		"iat":  time.Now().Unix(),
+		"type": "mfa_pending",
+	}
 
-// Log the change
-s.authLogRepo.Create(&models.AuthLog{
-UserID:    adminID,
-Action:    models.AuthActionUpdateStatus,
-IPAddress: ipAddress,
-UserAgent: userAgent,
-Status:    models.StatusSuccess,
-Details:   fmt.Sprintf("Updated user %s status to %s", userID, status),
-})
-
-// If account is suspended or deactivated, invalidate all sessions
-if status == models.UserStatusSuspended || status == models.UserStatusDeactivated {
-s.tokenRepo.DeleteAllForUser(userID)
-}
+	token, err := s.keyManager.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
 
-return nil
+	return token, expiresAt, nil
 }
 
 // generateTokenPair creates a new pair of access and refresh tokens
 func (s *AuthService) generateTokenPair(user *models.User) (*models.TokenPair, error) {
-// Generate unique token IDs
-accessJTI, err := generateRandomString(32)
-if err != nil {
-return nil, err
-}
-
-refreshJTI, err := generateRandomString(32)
-if err != nil {
-return nil, err
-}
-
-// Generate access token
-accessTokenClaims := jwt.MapClaims{
-"sub": user.ID,
-"exp": time.Now().Add(time.Duration(s.config.AccessTokenLifetimeMinutes) * time.Minute).Unix(),
-"iat": time.Now().Unix(),
-"jti": accessJTI,
-"type": "access",
-"role": user.Role,
-"email": user.Email,
-"name": user.Name,
-}
-
-accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
-accessTokenString, err := accessToken.SignedString([]byte(s.config.JWTSecret))
-if err != nil {
-return nil, fmt.Errorf("failed to sign access token: %w", err)
-}
-
-// Generate refresh token
-refreshTokenClaims := jwt.MapClaims{
-"sub": user.ID,
-"exp": time.Now().Add(time.Duration(s.config.RefreshTokenLifetimeDays) * 24 * time.Hour).Unix(),
-"iat": time.Now().Unix(),
-"jti": refreshJTI,
-"type": "refresh",
-}
-
-refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshTokenClaims)
-refreshTokenString, err := refreshToken.SignedString([]byte(s.config.JWTSecret))
-if err != nil {
-return nil, fmt.Errorf("failed to sign refresh token: %w", err)
-}
-
-return &models.TokenPair{
-AccessToken:       accessTokenString,
-RefreshToken:      refreshTokenString,
-AccessTokenHash:   accessJTI,
-RefreshTokenHash:  refreshJTI,
-}, nil
+	// Generate unique token IDs
+	accessJTI, err := generateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshJTI, err := generateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate access token
+	accessTokenClaims := jwt.MapClaims{
+		"sub":   strconv.FormatInt(user.ID, 10),
+		"exp":   time.Now().Add(time.Duration(s.config.AccessTokenLifetimeMinutes) * time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"jti":   accessJTI,
+		"type":  "access",
+		"role":  user.Role,
+		"email": user.Email,
+		"name":  user.FullName,
+	}
+
+	accessTokenString, err := s.keyManager.Sign(accessTokenClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	// Generate refresh token
+	refreshTokenClaims := jwt.MapClaims{
+		"sub":  strconv.FormatInt(user.ID, 10),
+		"exp":  time.Now().Add(time.Duration(s.config.RefreshTokenLifetimeDays) * 24 * time.Hour).Unix(),
+		"iat":  time.Now().Unix(),
+		"jti":  refreshJTI,
+		"type": "refresh",
+	}
+
+	refreshTokenString, err := s.keyManager.Sign(refreshTokenClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessTokenString,
+		RefreshToken:     refreshTokenString,
+		AccessTokenHash:  accessJTI,
+		RefreshTokenHash: refreshJTI,
+	}, nil
+}
+
+// hashResetToken hashes a password reset token for storage. Tokens are
+// random enough that an unsalted hash is safe, and it lets the token be
+// looked up directly.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 // generateRandomString creates a random string of specified length
 func generateRandomString(length int) (string, error) {
-bytes := make([]byte, length)
-if _, err := rand.Read(bytes); err != nil {
-return "", err
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(bytes)[:length], nil
 }
-return base64.URLEncoding.EncodeToString(bytes)[:length], nil
-}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// requirePermission loads the acting user and makes sure their role grants
+// the permission
+func requirePermission(userRepo *repository.UserRepository, actorID int64, permission models.Permission) (*models.User, error) {
+	actor, err := userRepo.GetByID(actorID)
+	if err != nil {
+		return nil, models.ErrUnauthorized
+	}
+
+	if !models.HasPermission(actor.Role, permission) {
+		return nil, models.ErrUnauthorized
+	}
+
+	return actor, nil
+}
+
+// requireSelfOrPermission lets users act on their own records and everyone
+// else only with the permission
+func requireSelfOrPermission(userRepo *repository.UserRepository, ownerID, actorID int64, permission models.Permission) error {
+	if ownerID == actorID && actorID != 0 {
+		return nil
+	}
+
+	_, err := requirePermission(userRepo, actorID, permission)
+	return err
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// BookCopyService handles item-level inventory business logic
+type BookCopyService struct {
+	db           *repository.Database
+	copyRepo     *repository.BookCopyRepository
+	bookRepo     *repository.BookRepository
+	activityRepo *repository.ActivityLogRepository
+}
+
+// NewBookCopyService creates a new BookCopyService instance
+func NewBookCopyService(
+	db *repository.Database,
+	copyRepo *repository.BookCopyRepository,
+	bookRepo *repository.BookRepository,
+	activityRepo *repository.ActivityLogRepository,
+) *BookCopyService {
+	return &BookCopyService{
+		db:           db,
+		copyRepo:     copyRepo,
+		bookRepo:     bookRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// AddCopy registers a new physical copy of a book
+func (s *BookCopyService) AddCopy(bookID int64, bookCopy *models.BookCopy, actor models.Actor) error {
+	bookCopy.Barcode = strings.TrimSpace(bookCopy.Barcode)
+	if bookCopy.Barcode == "" {
+		return models.ErrInvalidBarcode
+	}
+
+	// Make sure the book exists and has not been removed from the catalog
+	book, err := s.bookRepo.GetByID(bookID)
+	if err != nil {
+		return err
+	}
+
+	bookCopy.BookID = book.ID
+	bookCopy.Status = models.BookCopyStatusAvailable
+	if bookCopy.Location == "" {
+		bookCopy.Location = book.Location
+	}
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = "new"
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.copyRepo.Create(tx, bookCopy); err != nil {
+			if errors.Is(err, models.ErrDuplicateBarcode) {
+				return err
+			}
+			return fmt.Errorf("failed to create book copy: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityBookCopy, bookCopy.ID, nil, bookCopy)
+	})
+}
+
+// GetCopyByBarcode retrieves a copy by its barcode
+func (s *BookCopyService) GetCopyByBarcode(barcode string) (*models.BookCopy, error) {
+	return s.copyRepo.GetByBarcode(nil, strings.TrimSpace(barcode))
+}
+
+// ListCopies retrieves all copies of a book
+func (s *BookCopyService) ListCopies(bookID int64) ([]*models.BookCopy, error) {
+	if _, err := s.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	return s.copyRepo.ListByBook(bookID)
+}
+
+// MoveCopy changes the shelving location of a copy
+func (s *BookCopyService) MoveCopy(copyID int64, location string, actor models.Actor) error {
+	bookCopy, err := s.copyRepo.GetByID(copyID)
+	if err != nil {
+		return err
+	}
+
+	if bookCopy.RetiredAt != nil {
+		return models.ErrBookCopyRetired
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.copyRepo.UpdateLocation(tx, copyID, strings.TrimSpace(location)); err != nil {
+			return err
+		}
+
+		after := *bookCopy
+		after.Location = strings.TrimSpace(location)
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBookCopy, copyID, bookCopy, &after)
+	})
+}
+
+// RetireCopy marks a copy as lost, damaged or withdrawn
+func (s *BookCopyService) RetireCopy(copyID int64, status, notes string, actor models.Actor) error {
+	if !models.IsRetiredCopyStatus(status) {
+		return models.ErrInvalidCopyStatus
+	}
+
+	bookCopy, err := s.copyRepo.GetByID(copyID)
+	if err != nil {
+		return err
+	}
+
+	// Copies on loan are closed out through circulation instead
+	if bookCopy.Status == models.BookCopyStatusBorrowed {
+		return models.ErrBookCopyOnLoan
+	}
+
+	if bookCopy.RetiredAt != nil {
+		return models.ErrBookCopyRetired
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.copyRepo.Retire(tx, copyID, status, notes); err != nil {
+			return fmt.Errorf("failed to retire book copy: %w", err)
+		}
+
+		retiredAt := time.Now()
+		after := *bookCopy
+		after.Status = status
+		after.Notes = notes
+		after.RetiredAt = &retiredAt
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBookCopy, copyID, bookCopy, &after)
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// BookService handles catalog business logic
+type BookService struct {
+	db           *repository.Database
+	bookRepo     *repository.BookRepository
+	activityRepo *repository.ActivityLogRepository
+}
+
+// NewBookService creates a new BookService instance
+func NewBookService(
+	db *repository.Database,
+	bookRepo *repository.BookRepository,
+	activityRepo *repository.ActivityLogRepository,
+) *BookService {
+	return &BookService{
+		db:           db,
+		bookRepo:     bookRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// GetBook retrieves a book by ID
+func (s *BookService) GetBook(id int64) (*models.Book, error) {
+	return s.bookRepo.GetByID(id)
+}
+
+// GetBookByISBN retrieves a book by ISBN
+func (s *BookService) GetBookByISBN(isbn string) (*models.Book, error) {
+	return s.bookRepo.GetByISBN(strings.TrimSpace(isbn))
+}
+
+// CreateBook adds a new book to the catalog
+func (s *BookService) CreateBook(book *models.Book, actor models.Actor) error {
+	if err := validateBook(book); err != nil {
+		return err
+	}
+
+	// Check for an existing book with the same ISBN
+	if _, err := s.bookRepo.GetByISBN(book.ISBN); err == nil {
+		return models.ErrDuplicateISBN
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.bookRepo.Create(tx, book); err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityBook, book.ID, nil, book)
+	})
+}
+
+// UpdateBook updates a book's catalog data
+func (s *BookService) UpdateBook(book *models.Book, actor models.Actor) error {
+	if err := validateBook(book); err != nil {
+		return err
+	}
+
+	// Make sure the ISBN is not taken by another book
+	existing, err := s.bookRepo.GetByISBN(book.ISBN)
+	if err == nil && existing.ID != book.ID {
+		return models.ErrDuplicateISBN
+	}
+
+	before, err := s.bookRepo.GetByID(book.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.bookRepo.Update(tx, book); err != nil {
+			return fmt.Errorf("failed to update book: %w", err)
+		}
+
+		// Copy counts are not updated here, so keep the stored ones in the diff
+		after := *book
+		after.TotalCopies = before.TotalCopies
+		after.AvailableCopies = before.AvailableCopies
+		after.CreatedAt = before.CreatedAt
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBook, book.ID, before, &after)
+	})
+}
+
+// DeleteBook removes a book from the catalog
+func (s *BookService) DeleteBook(id int64, actor models.Actor) error {
+	book, err := s.bookRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to remove titles while copies are out on loan
+	if book.AvailableCopies < book.TotalCopies {
+		return models.ErrBookHasActiveLoans
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.bookRepo.Delete(tx, id); err != nil {
+			return fmt.Errorf("failed to delete book: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityDelete, models.EntityBook, id, book, nil)
+	})
+}
+
+// ListBooks retrieves a page of books and the total number of matching books
+func (s *BookService) ListBooks(page, pageSize int, filters models.BookFilters) ([]*models.Book, int, error) {
+	books, err := s.bookRepo.List(page, pageSize, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list books: %w", err)
+	}
+
+	total, err := s.bookRepo.Count(filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count books: %w", err)
+	}
+
+	return books, total, nil
+}
+
+// validateBook normalizes and checks the required catalog fields
+func validateBook(book *models.Book) error {
+	book.ISBN = strings.TrimSpace(book.ISBN)
+	book.Title = strings.TrimSpace(book.Title)
+	book.Author = strings.TrimSpace(book.Author)
+
+	if book.ISBN == "" || book.Title == "" || book.Author == "" {
+		return models.ErrInvalidBook
+	}
+
+	return nil
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// BorrowingService handles circulation business logic
+type BorrowingService struct {
+	db            *repository.Database
+	borrowingRepo *repository.BorrowingRepository
+	bookRepo      *repository.BookRepository
+	copyRepo      *repository.BookCopyRepository
+	userRepo      *repository.UserRepository
+	policyRepo    *repository.CirculationPolicyRepository
+	fineRepo      *repository.FineRepository
+	calendarRepo  *repository.LibraryCalendarRepository
+	ledgerRepo    *repository.LedgerRepository
+	activityRepo  *repository.ActivityLogRepository
+
+	reservationService *ReservationService
+}
+
+// NewBorrowingService creates a new BorrowingService instance
+func NewBorrowingService(
+	db *repository.Database,
+	borrowingRepo *repository.BorrowingRepository,
+	bookRepo *repository.BookRepository,
+	copyRepo *repository.BookCopyRepository,
+	userRepo *repository.UserRepository,
+	policyRepo *repository.CirculationPolicyRepository,
+	fineRepo *repository.FineRepository,
+	calendarRepo *repository.LibraryCalendarRepository,
+	ledgerRepo *repository.LedgerRepository,
+	activityRepo *repository.ActivityLogRepository,
+	reservationService *ReservationService,
+) *BorrowingService {
+	return &BorrowingService{
+		db:            db,
+		borrowingRepo: borrowingRepo,
+		bookRepo:      bookRepo,
+		copyRepo:      copyRepo,
+		userRepo:      userRepo,
+		policyRepo:    policyRepo,
+		fineRepo:      fineRepo,
+		calendarRepo:  calendarRepo,
+		ledgerRepo:    ledgerRepo,
+		activityRepo:  activityRepo,
+
+		reservationService: reservationService,
+	}
+}
+
+// Checkout lends the copy with the given barcode to a user on behalf of the
+// staff member acting
+func (s *BorrowingService) Checkout(userID int64, copyBarcode string, actor models.Actor) (*models.Borrowing, error) {
+	// Get user
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check account status
+	if user.AccountStatus == models.UserStatusSuspended {
+		return nil, models.ErrAccountSuspended
+	}
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+
+	var borrowing *models.Borrowing
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		// Serialize checkouts for this user so the loan limit holds
+		if err := s.userRepo.LockForUpdate(tx, user.ID); err != nil {
+			return err
+		}
+
+		// Fines on loans still out count as well as those on the ledger
+		account, err := s.ledgerRepo.Balance(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		accruing, err := s.borrowingRepo.SumAccruingFines(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if roundCents(account.Balance+accruing) > 0 {
+			return models.ErrUnpaidFines
+		}
+
+		// Lock the copy being checked out
+		bookCopy, err := s.copyRepo.GetByBarcode(tx, strings.TrimSpace(copyBarcode))
+		if err != nil {
+			return err
+		}
+
+		// A reserved copy may only go to the patron it was set aside for
+		switch bookCopy.Status {
+		case models.BookCopyStatusAvailable:
+		case models.BookCopyStatusReserved:
+			if err := s.reservationService.ClaimHoldForCheckout(tx, bookCopy.ID, user.ID); err != nil {
+				return err
+			}
+		default:
+			return models.ErrBookCopyNotAvailable
+		}
+
+		// Titles removed from the catalog can no longer circulate
+		book, err := s.bookRepo.GetByID(bookCopy.BookID)
+		if err != nil {
+			return err
+		}
+
+		policy, err := s.policyRepo.Resolve(tx, user.Role, book.Category)
+		if err != nil {
+			return err
+		}
+
+		activeLoans, err := s.borrowingRepo.CountActiveByUser(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if activeLoans >= policy.MaxLoans {
+			return models.ErrLoanLimitReached
+		}
+
+		now := time.Now()
+		staffID := actor.UserID
+		borrowing = &models.Borrowing{
+			UserID:          user.ID,
+			BookCopyID:      bookCopy.ID,
+			BorrowedDate:    now,
+			DueDate:         now.AddDate(0, 0, policy.LoanPeriodDays),
+			Status:          models.BorrowingStatusActive,
+			StaffIDCheckout: &staffID,
+		}
+
+		if err := s.borrowingRepo.Create(tx, borrowing); err != nil {
+			return err
+		}
+
+		if err := s.borrowingRepo.UpdateBookCopyStatus(tx, bookCopy.ID, models.BookCopyStatusBorrowed); err != nil {
+			return err
+		}
+
+		if err := s.borrowingRepo.SyncBookCounts(tx, bookCopy.BookID); err != nil {
+			return err
+		}
+
+		created, err := s.borrowingRepo.Reload(tx, borrowing.ID)
+		if err != nil {
+			return err
+		}
+		return recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityBorrowing, created.ID, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.borrowingRepo.GetByID(borrowing.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load borrowing: %w", err)
+	}
+
+	return created, nil
+}
+
+// Return checks a borrowed copy back in. The fine is calculated from the
+// circulation policy and the library calendar, and every calculation is
+// recorded as a fine assessment in the same transaction.
+func (s *BorrowingService) Return(borrowingID int64, actor models.Actor) (*models.Borrowing, error) {
+	borrowing, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrowing.ReturnedDate != nil {
+		return nil, models.ErrBorrowingAlreadyReturned
+	}
+
+	staffID := actor.UserID
+	returnedDate := time.Now()
+	var readyHold *models.Reservation
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		policy, err := s.policyRepo.ResolveForBorrowing(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+
+		assessment, err := s.calculateFine(tx, policy, borrowing, returnedDate)
+		if err != nil {
+			return err
+		}
+
+		if err := s.borrowingRepo.Return(tx, borrowingID, returnedDate, staffID, assessment.Amount); err != nil {
+			return err
+		}
+
+		returned, err := s.borrowingRepo.Reload(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+		err = recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBorrowing, borrowingID, borrowing, returned)
+		if err != nil {
+			return err
+		}
+
+		// Hand the copy to the next patron waiting for this title
+		readyHold, err = s.reservationService.SetAsideForNextHold(tx, borrowing.BookID, borrowing.BookCopyID)
+		if err != nil {
+			return err
+		}
+
+		// Only late returns leave an assessment behind
+		if assessment.DaysLate == 0 {
+			return nil
+		}
+
+		if err := s.fineRepo.CreateAssessment(tx, assessment); err != nil {
+			return err
+		}
+
+		err = recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityFine, assessment.ID, nil, assessment)
+		if err != nil {
+			return err
+		}
+
+		if assessment.Amount == 0 {
+			return nil
+		}
+
+		// Charge the fine to the patron's account
+		return s.ledgerRepo.CreateEntry(tx, &models.LedgerEntry{
+			UserID:      borrowing.UserID,
+			BorrowingID: &borrowing.ID,
+			EntryType:   models.LedgerEntryCharge,
+			Amount:      assessment.Amount,
+			Description: fmt.Sprintf("Overdue fine: %s", borrowing.BookTitle),
+			StaffID:     &staffID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reservationService.AnnounceReady(readyHold)
+
+	returned, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load borrowing: %w", err)
+	}
+
+	return returned, nil
+}
+
+// Renew extends a borrowing by another loan period. Patrons may renew their
+// own loans; staff may renew any loan. Only loans that are not yet past their
+// due date can be renewed: moving the due date of an overdue loan would wipe
+// out the fine it has accrued, so it has to be returned instead.
+func (s *BorrowingService) Renew(borrowingID int64, actor models.Actor) (*models.Borrowing, error) {
+	renewer, err := s.userRepo.GetByID(actor.UserID)
+	if err != nil {
+		return nil, models.ErrUnauthorized
+	}
+
+	borrowing, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrowing.UserID != renewer.ID && !models.HasPermission(renewer.Role, models.PermLoansManage) {
+		return nil, models.ErrUnauthorized
+	}
+
+	if borrowing.ReturnedDate != nil {
+		return nil, models.ErrBorrowingAlreadyReturned
+	}
+	if borrowing.Status == models.BorrowingStatusOverdue {
+		return nil, models.ErrLoanOverdue
+	}
+	if borrowing.Status != models.BorrowingStatusActive {
+		return nil, models.ErrCannotRenew
+	}
+
+	// The borrower's account must be in good standing
+	borrower, err := s.userRepo.GetByID(borrowing.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if borrower.AccountStatus == models.UserStatusSuspended {
+		return nil, models.ErrAccountSuspended
+	}
+	if borrower.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		dueDate, bookID, err := s.borrowingRepo.LockOpenLoan(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+
+		// Loans the overdue sweep has not reached yet are refused the same way
+		if dueDate.Before(time.Now()) {
+			return models.ErrLoanOverdue
+		}
+
+		policy, err := s.policyRepo.ResolveForBorrowing(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+
+		renewals, err := s.borrowingRepo.CountRenewals(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+		if renewals >= policy.MaxRenewals {
+			return models.ErrMaxRenewalsReached
+		}
+
+		onHold, err := s.borrowingRepo.HasHoldsByOthers(tx, bookID, borrowing.UserID)
+		if err != nil {
+			return err
+		}
+		if onHold {
+			return models.ErrTitleOnHold
+		}
+
+		newDueDate := dueDate.AddDate(0, 0, policy.LoanPeriodDays)
+		if err := s.borrowingRepo.UpdateDueDate(tx, borrowingID, newDueDate); err != nil {
+			return err
+		}
+
+		err = s.borrowingRepo.CreateRenewal(tx, &models.LoanRenewal{
+			BorrowingID:     borrowingID,
+			PreviousDueDate: dueDate,
+			NewDueDate:      newDueDate,
+			RenewedBy:       &renewer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		renewed, err := s.borrowingRepo.Reload(tx, borrowingID)
+		if err != nil {
+			return err
+		}
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityBorrowing, borrowingID, borrowing, renewed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	renewed, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load borrowing: %w", err)
+	}
+
+	return renewed, nil
+}
+
+// ListRenewals retrieves the renewal history of a borrowing. Borrowers see
+// their own loans; anyone else needs to manage loans.
+func (s *BorrowingService) ListRenewals(borrowingID, actorID int64) ([]*models.LoanRenewal, error) {
+	borrowing, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireSelfOrPermission(s.userRepo, borrowing.UserID, actorID, models.PermLoansManage); err != nil {
+		return nil, err
+	}
+
+	return s.borrowingRepo.ListRenewals(borrowingID)
+}
+
+// ListFineAssessments retrieves the fine calculations made for a borrowing,
+// under the same access rule as ListRenewals
+func (s *BorrowingService) ListFineAssessments(borrowingID, actorID int64) ([]*models.FineAssessment, error) {
+	borrowing, err := s.borrowingRepo.GetByID(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireSelfOrPermission(s.userRepo, borrowing.UserID, actorID, models.PermLoansManage); err != nil {
+		return nil, err
+	}
+
+	return s.fineRepo.ListAssessmentsByBorrowing(borrowingID)
+}
+
+// calculateFine assesses the fine for a borrowing up to the given time
+func (s *BorrowingService) calculateFine(tx *sql.Tx, policy *models.CirculationPolicy, borrowing *models.Borrowing, until time.Time) (*models.FineAssessment, error) {
+	if !until.After(borrowing.DueDate) {
+		return assessFine(policy, borrowing, until, nil), nil
+	}
+
+	closedDays, err := s.calendarRepo.ListClosedDays(tx, borrowing.DueDate, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load closed days: %w", err)
+	}
+
+	return assessFine(policy, borrowing, until, closedDays), nil
+}
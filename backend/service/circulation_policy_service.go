@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// CirculationPolicyService manages the circulation policy matrix
+type CirculationPolicyService struct {
+	policyRepo   *repository.CirculationPolicyRepository
+	calendarRepo *repository.LibraryCalendarRepository
+	userRepo     *repository.UserRepository
+}
+
+// NewCirculationPolicyService creates a new CirculationPolicyService instance
+func NewCirculationPolicyService(
+	policyRepo *repository.CirculationPolicyRepository,
+	calendarRepo *repository.LibraryCalendarRepository,
+	userRepo *repository.UserRepository,
+) *CirculationPolicyService {
+	return &CirculationPolicyService{
+		policyRepo:   policyRepo,
+		calendarRepo: calendarRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// ListPolicies retrieves every circulation policy
+func (s *CirculationPolicyService) ListPolicies() ([]*models.CirculationPolicy, error) {
+	return s.policyRepo.List()
+}
+
+// SavePolicy creates or replaces the policy for a role and category
+func (s *CirculationPolicyService) SavePolicy(policy *models.CirculationPolicy, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermPoliciesManage); err != nil {
+		return err
+	}
+
+	policy.Category = strings.TrimSpace(policy.Category)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	policy.UpdatedBy = &adminID
+	if err := s.policyRepo.Save(policy); err != nil {
+		return fmt.Errorf("failed to save circulation policy: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePolicy removes a category-specific policy
+func (s *CirculationPolicyService) DeletePolicy(id, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermPoliciesManage); err != nil {
+		return err
+	}
+
+	policy, err := s.policyRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Every role needs a fallback policy for checkout to work
+	if policy.Category == "" {
+		return models.ErrDefaultPolicyRequired
+	}
+
+	return s.policyRepo.Delete(id)
+}
+
+// ListClosedDays retrieves the closed days between from and to, inclusive
+func (s *CirculationPolicyService) ListClosedDays(from, to time.Time) ([]*models.ClosedDay, error) {
+	if to.Before(from) {
+		return nil, models.ErrInvalidClosedDay
+	}
+
+	return s.calendarRepo.ListClosedDays(nil, from, to)
+}
+
+// AddClosedDay marks a date on which no fines accrue
+func (s *CirculationPolicyService) AddClosedDay(day *models.ClosedDay, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermPoliciesManage); err != nil {
+		return err
+	}
+
+	if day.Date.IsZero() {
+		return models.ErrInvalidClosedDay
+	}
+
+	day.Reason = strings.TrimSpace(day.Reason)
+	if err := s.calendarRepo.AddClosedDay(day); err != nil {
+		return fmt.Errorf("failed to add closed day: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteClosedDay reopens a previously closed date
+func (s *CirculationPolicyService) DeleteClosedDay(date time.Time, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermPoliciesManage); err != nil {
+		return err
+	}
+
+	return s.calendarRepo.DeleteClosedDay(date)
+}
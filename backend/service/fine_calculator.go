@@ -0,0 +1,60 @@
+package service
+
+import (
+	"time"
+
+	"library-management-system/internal/models"
+)
+
+// dateLayout formats calendar dates for closed day lookups
+const dateLayout = "2006-01-02"
+
+// assessFine works out the fine owed on a borrowing from its due date until
+// the given time. Lateness is counted in calendar days in the library's local
+// time. Days the library is closed are not charged, and the first GraceDays
+// open days are free.
+func assessFine(policy *models.CirculationPolicy, borrowing *models.Borrowing, until time.Time, closedDays []*models.ClosedDay) *models.FineAssessment {
+	assessment := &models.FineAssessment{
+		BorrowingID:   borrowing.ID,
+		UserID:        borrowing.UserID,
+		PolicyID:      policy.ID,
+		DueDate:       borrowing.DueDate,
+		AssessedUntil: until,
+		GraceDays:     policy.GraceDays,
+		DailyRate:     policy.DailyFine,
+		FineCap:       policy.FineCap,
+	}
+
+	closed := make(map[string]bool, len(closedDays))
+	for _, day := range closedDays {
+		closed[day.Date.Format(dateLayout)] = true
+	}
+
+	// Count every calendar day after the due date up to and including the last day
+	lastDay := startOfDay(until)
+	for day := startOfDay(borrowing.DueDate).AddDate(0, 0, 1); !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		assessment.DaysLate++
+		if closed[day.Format(dateLayout)] {
+			assessment.ClosedDays++
+		}
+	}
+
+	assessment.ChargeableDays = assessment.DaysLate - assessment.ClosedDays - assessment.GraceDays
+	if assessment.ChargeableDays < 0 {
+		assessment.ChargeableDays = 0
+	}
+
+	amount := float64(assessment.ChargeableDays) * policy.DailyFine
+	if policy.FineCap > 0 && amount > policy.FineCap {
+		amount = policy.FineCap
+	}
+	assessment.Amount = roundCents(amount)
+
+	return assessment
+}
+
+// startOfDay returns midnight of t's calendar day in local time
+func startOfDay(t time.Time) time.Time {
+	local := t.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// HistoryService reads the audit trail of catalog, circulation and user
+// records
+type HistoryService struct {
+	activityRepo *repository.ActivityLogRepository
+	userRepo     *repository.UserRepository
+}
+
+// NewHistoryService creates a new HistoryService instance
+func NewHistoryService(activityRepo *repository.ActivityLogRepository, userRepo *repository.UserRepository) *HistoryService {
+	return &HistoryService{
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// EntityHistory returns a page of the changes made to an entity, newest
+// first, and the total number of changes
+func (s *HistoryService) EntityHistory(actorID int64, entityType string, entityID int64, page, pageSize int) ([]*models.ActivityLog, int, error) {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermAuditLogsRead); err != nil {
+		return nil, 0, err
+	}
+
+	if !models.IsAuditedEntity(entityType) {
+		return nil, 0, models.ErrUnknownEntityType
+	}
+
+	logs, err := s.activityRepo.ListByEntity(entityType, entityID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list history: %w", err)
+	}
+
+	total, err := s.activityRepo.CountByEntity(entityType, entityID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count history: %w", err)
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported asymmetric signing algorithms
+const (
+	signingAlgorithmRS256 = "RS256"
+	signingAlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+
+	// keyReloadMinInterval limits reloads triggered by tokens with an unknown kid
+	keyReloadMinInterval = 10 * time.Second
+)
+
+// loadedKey is a decrypted signing key held in memory
+type loadedKey struct {
+	id        string
+	algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+}
+
+// KeyManager signs JWTs with the newest asymmetric key, verifies tokens
+// against every key that has not expired and rotates keys on a schedule
+type KeyManager struct {
+	signingKeyRepo *repository.SigningKeyRepository
+	logger         *logger.Logger
+
+	secrets     *secretBox
+	algorithm   string
+	rotateAfter time.Duration
+	retainFor   time.Duration
+	interval    time.Duration
+
+	// legacySecret verifies HS256 tokens issued before asymmetric signing,
+	// until legacyUntil, when they have all expired
+	legacySecret []byte
+	legacyUntil  time.Time
+
+	mu         sync.RWMutex
+	signing    *loadedKey
+	keys       map[string]*loadedKey
+	lastReload time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewKeyManager creates a new KeyManager and loads its keys, creating the
+// first key when there is none. retainFor is the longest lifetime of any
+// token, which is how long retired keys keep verifying.
+func NewKeyManager(
+	signingKeyRepo *repository.SigningKeyRepository,
+	logger *logger.Logger,
+	cfg config.SigningKeyConfig,
+	legacySecret string,
+	retainFor time.Duration,
+) (*KeyManager, error) {
+	if cfg.Algorithm != signingAlgorithmRS256 && cfg.Algorithm != signingAlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", cfg.Algorithm)
+	}
+
+	secrets, err := newSecretBox(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var legacyUntil time.Time
+	if cfg.LegacyRolloutAt != "" {
+		rolloutAt, err := time.Parse(time.RFC3339, cfg.LegacyRolloutAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid legacy rollout time %q: %w", cfg.LegacyRolloutAt, err)
+		}
+		legacyUntil = rolloutAt.Add(retainFor)
+	}
+
+	m := &KeyManager{
+		signingKeyRepo: signingKeyRepo,
+		logger:         logger,
+
+		secrets:     secrets,
+		algorithm:   cfg.Algorithm,
+		rotateAfter: time.Duration(cfg.RotationDays) * 24 * time.Hour,
+		retainFor:   retainFor,
+		interval:    time.Duration(cfg.CheckIntervalMinutes) * time.Minute,
+
+		legacySecret: []byte(legacySecret),
+		legacyUntil:  legacyUntil,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if err := m.Refresh(time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Without a configured rollout time, asymmetric signing went live when
+	// the first key was created, which Refresh has made sure of
+	if cfg.LegacyRolloutAt == "" {
+		firstKeyAt, ok, err := signingKeyRepo.FirstCreatedAt()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signing key age: %w", err)
+		}
+		if ok {
+			m.legacyUntil = firstKeyAt.Add(retainFor)
+		}
+	}
+
+	return m, nil
+}
+
+// Start reloads keys and rotates the signing key when it is due, on every
+// interval until Stop is called
+func (m *KeyManager) Start() {
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Refresh(time.Now()); err != nil {
+					m.logger.Error("Signing key refresh failed", "error", err)
+				}
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the key manager to finish and waits for it
+func (m *KeyManager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	<-m.done
+}
+
+// Refresh loads the usable keys and rotates the signing key when it is older
+// than the rotation period
+func (m *KeyManager) Refresh(now time.Time) error {
+	if err := m.reload(now); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	due := m.signing == nil || m.signing.algorithm != m.algorithm || now.Sub(m.signing.createdAt) >= m.rotateAfter
+	m.mu.RUnlock()
+
+	if !due {
+		return nil
+	}
+
+	return m.Rotate(now)
+}
+
+// Rotate creates a new signing key and retires the previous ones. Tokens they
+// signed keep verifying until they expire.
+func (m *KeyManager) Rotate(now time.Time) error {
+	key, err := m.generateKey(now)
+	if err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	if err := m.signingKeyRepo.Create(key); err != nil {
+		return fmt.Errorf("failed to save signing key: %w", err)
+	}
+	if err := m.signingKeyRepo.RetireOthers(key.ID, now, now.Add(m.retainFor)); err != nil {
+		return fmt.Errorf("failed to retire signing keys: %w", err)
+	}
+
+	m.logger.Info("Rotated JWT signing key", "kid", key.ID, "algorithm", key.Algorithm)
+
+	return m.reload(now)
+}
+
+// Sign signs claims with the current key and sets the kid header
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	m.mu.RLock()
+	key := m.signing
+	m.mu.RUnlock()
+
+	if key == nil {
+		return "", fmt.Errorf("no signing key loaded")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.algorithm), claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.private)
+}
+
+// ValidMethods lists the algorithms tokens may be signed with
+func (m *KeyManager) ValidMethods() []string {
+	methods := []string{signingAlgorithmRS256, signingAlgorithmEdDSA}
+	if m.acceptsLegacy(time.Now()) {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	return methods
+}
+
+// Keyfunc returns the verification key for a token's kid, making sure the
+// token's algorithm matches the key
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() && m.acceptsLegacy(time.Now()) {
+			return m.legacySecret, nil
+		}
+		return nil, fmt.Errorf("token has no key id")
+	}
+
+	key := m.lookup(kid)
+	if key == nil {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.public, nil
+}
+
+// acceptsLegacy reports whether HS256 tokens from before the rollout may
+// still be live
+func (m *KeyManager) acceptsLegacy(now time.Time) bool {
+	return len(m.legacySecret) > 0 && now.Before(m.legacyUntil)
+}
+
+// JWKS returns the public keys that currently verify tokens
+func (m *KeyManager) JWKS() *models.JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jwks := &models.JWKS{Keys: make([]models.JWK, 0, len(m.keys))}
+	for _, key := range m.keys {
+		jwk := models.JWK{KeyID: key.id, Use: "sig", Algorithm: key.algorithm}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+// lookup finds a verification key, reloading from the database when another
+// instance may have rotated in a key this one has not seen yet
+func (m *KeyManager) lookup(kid string) *loadedKey {
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	stale := time.Since(m.lastReload) >= keyReloadMinInterval
+	m.mu.RUnlock()
+
+	if ok || !stale {
+		return key
+	}
+
+	if err := m.reload(time.Now()); err != nil {
+		m.logger.Error("Failed to reload signing keys", "error", err)
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys[kid]
+}
+
+// reload replaces the in-memory keys with the usable keys in the database
+func (m *KeyManager) reload(now time.Time) error {
+	stored, err := m.signingKeyRepo.ListUsable(now)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make(map[string]*loadedKey, len(stored))
+	var signing *loadedKey
+
+	for _, record := range stored {
+		key, err := m.decodeKey(record)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key %s: %w", record.ID, err)
+		}
+
+		keys[key.id] = key
+		if signing == nil && record.RetiredAt == nil {
+			signing = key
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.signing = signing
+	m.lastReload = now
+	m.mu.Unlock()
+
+	return nil
+}
+
+// generateKey creates a key pair for the configured algorithm
+func (m *KeyManager) generateKey(now time.Time) (*models.SigningKey, error) {
+	var private crypto.Signer
+	switch m.algorithm {
+	case signingAlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := m.secrets.seal(base64.StdEncoding.EncodeToString(privateDER))
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &models.SigningKey{
+		ID:         hex.EncodeToString(id),
+		Algorithm:  m.algorithm,
+		PrivateKey: sealed,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		CreatedAt:  now,
+	}, nil
+}
+
+// decodeKey decrypts and parses a stored key
+func (m *KeyManager) decodeKey(record *models.SigningKey) (*loadedKey, error) {
+	encoded, err := m.secrets.open(record.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		if record.Algorithm != signingAlgorithmRS256 {
+			return nil, fmt.Errorf("RSA key stored as %s", record.Algorithm)
+		}
+	case ed25519.PrivateKey:
+		if record.Algorithm != signingAlgorithmEdDSA {
+			return nil, fmt.Errorf("Ed25519 key stored as %s", record.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return &loadedKey{
+		id:        record.ID,
+		algorithm: record.Algorithm,
+		private:   private,
+		public:    private.Public(),
+		createdAt: record.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// LockoutService locks accounts after repeated failed logins and throttles
+// login attempts per client address
+type LockoutService struct {
+	db          *repository.Database
+	userRepo    *repository.UserRepository
+	authLogRepo *repository.AuthLogRepository
+
+	config   config.LockoutConfig
+	throttle *loginThrottle
+}
+
+// NewLockoutService creates a new LockoutService instance
+func NewLockoutService(
+	db *repository.Database,
+	userRepo *repository.UserRepository,
+	authLogRepo *repository.AuthLogRepository,
+	cfg config.LockoutConfig,
+) *LockoutService {
+	return &LockoutService{
+		db:          db,
+		userRepo:    userRepo,
+		authLogRepo: authLogRepo,
+
+		config:   cfg,
+		throttle: newLoginThrottle(cfg.IPMaxAttempts, time.Duration(cfg.IPWindowMinutes)*time.Minute),
+	}
+}
+
+// CheckIP refuses logins from an address with too many recent failures
+func (s *LockoutService) CheckIP(ipAddress string) error {
+	if !s.throttle.allow(ipAddress, time.Now()) {
+		return models.ErrTooManyLoginAttempts
+	}
+	return nil
+}
+
+// CheckUser refuses logins to a locked account. Locks expire on their own.
+func (s *LockoutService) CheckUser(userID int64) error {
+	status, err := s.userRepo.GetLockoutStatus(nil, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load lockout status: %w", err)
+	}
+
+	if status.IsLocked(time.Now()) {
+		return models.ErrAccountLocked
+	}
+	return nil
+}
+
+// RecordIPFailure counts a failed login against the client address
+func (s *LockoutService) RecordIPFailure(ipAddress string) {
+	s.throttle.fail(ipAddress, time.Now())
+}
+
+// RecordFailure counts a failed login against an account and locks it once
+// the configured number of failures in a row is reached
+func (s *LockoutService) RecordFailure(userID int64, ipAddress, userAgent string) error {
+	var lockedUntil *time.Time
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		status, err := s.userRepo.GetLockoutStatus(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if status.LockedUntil != nil && !status.IsLocked(now) {
+			// The previous lock ran out; start counting afresh
+			status.LockedUntil = nil
+		}
+
+		status.FailedAttempts++
+		if status.FailedAttempts >= s.config.MaxFailedAttempts {
+			until := now.Add(s.lockoutDuration(status.LockoutCount))
+			status.LockedUntil = &until
+			status.LockoutCount++
+			status.FailedAttempts = 0
+			lockedUntil = &until
+		}
+
+		return s.userRepo.SaveLockoutStatus(tx, userID, status)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	if lockedUntil != nil {
+		s.authLogRepo.CreateWithSeverity(&models.AuthLog{
+			UserID:    userID,
+			Action:    models.AuthActionAccountLocked,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Status:    models.StatusFailure,
+			Severity:  models.AuthSeverityHigh,
+			Details:   fmt.Sprintf("Locked until %s", lockedUntil.Format(time.RFC3339)),
+		})
+	}
+
+	return nil
+}
+
+// RecordSuccess clears an account's failed attempts after a login
+func (s *LockoutService) RecordSuccess(userID int64) error {
+	return s.userRepo.ResetLockout(userID)
+}
+
+// GetStatus returns a user's failed login state for admins
+func (s *LockoutService) GetStatus(userID, actorID int64) (*models.LockoutStatus, error) {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetLockoutStatus(nil, userID)
+}
+
+// Unlock lets an admin lift a lockout before it expires
+func (s *LockoutService) Unlock(userID, actorID int64, ipAddress, userAgent string) error {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ResetLockout(userID); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    userID,
+		Action:    models.AuthActionAccountUnlocked,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+		Details:   fmt.Sprintf("Unlocked by user %d", actorID),
+	})
+
+	return nil
+}
+
+// lockoutDuration doubles the base lockout for every earlier lockout
+func (s *LockoutService) lockoutDuration(previousLockouts int) time.Duration {
+	duration := time.Duration(s.config.BaseLockoutMinutes) * time.Minute
+	limit := time.Duration(s.config.MaxLockoutMinutes) * time.Minute
+
+	for i := 0; i < previousLockouts && duration < limit; i++ {
+		duration *= 2
+	}
+
+	if duration > limit {
+		return limit
+	}
+	return duration
+}
+
+// loginThrottle counts failed logins per client address over a sliding
+// window. It is kept in memory, so each API instance throttles on its own.
+type loginThrottle struct {
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	limit    int
+	window   time.Duration
+}
+
+// newLoginThrottle creates a throttle allowing limit failures per window
+func newLoginThrottle(limit int, window time.Duration) *loginThrottle {
+	return &loginThrottle{
+		failures: make(map[string][]time.Time),
+		limit:    limit,
+		window:   window,
+	}
+}
+
+// allow reports whether an address may attempt another login
+func (t *loginThrottle) allow(ipAddress string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.recent(ipAddress, now)) < t.limit
+}
+
+// fail records a failed login from an address
+func (t *loginThrottle) fail(ipAddress string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures[ipAddress] = append(t.recent(ipAddress, now), now)
+
+	// Drop idle addresses now and then so the map does not grow forever
+	if len(t.failures) > 10000 {
+		for ip := range t.failures {
+			if len(t.recent(ip, now)) == 0 {
+				delete(t.failures, ip)
+			}
+		}
+	}
+}
+
+// recent prunes and returns the failures of an address inside the window.
+// Callers must hold the lock.
+func (t *loginThrottle) recent(ipAddress string, now time.Time) []time.Time {
+	failures := t.failures[ipAddress]
+
+	cutoff := now.Add(-t.window)
+	kept := failures[:0]
+	for _, at := range failures {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(t.failures, ipAddress)
+		return nil
+	}
+
+	t.failures[ipAddress] = kept
+	return kept
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+)
+
+// NotificationChannel delivers rendered messages to patrons
+type NotificationChannel interface {
+	// Name identifies the channel in logs
+	Name() string
+
+	// Send delivers a single message
+	Send(message *models.NotificationMessage) error
+}
+
+// SMTPChannel sends notifications as plain text email
+type SMTPChannel struct {
+	addr string
+	auth smtp.Auth
+	from mail.Address
+}
+
+// NewSMTPChannel creates a new SMTPChannel instance. Authentication is only
+// used when a username is configured, so a local fake SMTP server works
+// without credentials.
+func NewSMTPChannel(cfg config.NotificationConfig) *SMTPChannel {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return &SMTPChannel{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		auth: auth,
+		from: mail.Address{Name: cfg.FromName, Address: cfg.FromAddress},
+	}
+}
+
+// Name identifies the channel in logs
+func (c *SMTPChannel) Name() string {
+	return "smtp"
+}
+
+// Send delivers a message to the patron's email address
+func (c *SMTPChannel) Send(message *models.NotificationMessage) error {
+	if message.To == "" {
+		return fmt.Errorf("no email address for %s notification", message.Type)
+	}
+
+	to := mail.Address{Name: message.Name, Address: message.To}
+
+	var body strings.Builder
+	body.WriteString("From: " + c.from.String() + "\r\n")
+	body.WriteString("To: " + to.String() + "\r\n")
+	body.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	body.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	err := smtp.SendMail(c.addr, c.auth, c.from.Address, []string{to.Address}, []byte(body.String()))
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+)
+
+// smtpSession is what the fake SMTP server received in one connection
+type smtpSession struct {
+	auth       string
+	from       string
+	recipients []string
+	data       string
+}
+
+// fakeSMTPServer accepts a single connection and speaks just enough SMTP for
+// net/smtp. Recipients listed in reject are refused.
+type fakeSMTPServer struct {
+	listener  net.Listener
+	sessions  chan smtpSession
+	authPlain bool
+	reject    map[string]bool
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	return &fakeSMTPServer{
+		listener: listener,
+		sessions: make(chan smtpSession, 1),
+		reject:   make(map[string]bool),
+	}
+}
+
+// config points an SMTP channel at the fake server
+func (s *fakeSMTPServer) config() config.NotificationConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	return config.NotificationConfig{
+		SMTPHost:    host,
+		SMTPPort:    portNumber,
+		FromAddress: "library@example.org",
+		FromName:    "City Library",
+	}
+}
+
+func (s *fakeSMTPServer) serve() {
+	go func() {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		text := textproto.NewConn(conn)
+		var session smtpSession
+		defer func() { s.sessions <- session }()
+
+		text.PrintfLine("220 localhost fake SMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"):
+				if s.authPlain {
+					text.PrintfLine("250-localhost")
+					text.PrintfLine("250 AUTH PLAIN")
+				} else {
+					text.PrintfLine("250 localhost")
+				}
+			case strings.HasPrefix(command, "AUTH PLAIN"):
+				session.auth = strings.TrimSpace(line[len("AUTH PLAIN"):])
+				text.PrintfLine("235 authenticated")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				if i := strings.Index(session.from, ">"); i >= 0 {
+					session.from = session.from[:i]
+				}
+				text.PrintfLine("250 ok")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				recipient := strings.Trim(line[len("RCPT TO:"):], "<> ")
+				if s.reject[recipient] {
+					text.PrintfLine("550 no such user")
+					continue
+				}
+				session.recipients = append(session.recipients, recipient)
+				text.PrintfLine("250 ok")
+			case command == "DATA":
+				text.PrintfLine("354 go ahead")
+				data, err := io.ReadAll(text.DotReader())
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				text.PrintfLine("250 queued")
+			case command == "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+}
+
+func (s *fakeSMTPServer) session(t *testing.T) smtpSession {
+	t.Helper()
+
+	select {
+	case session := <-s.sessions:
+		return session
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake SMTP server got no connection")
+		return smtpSession{}
+	}
+}
+
+func TestSMTPChannelSendsPlainTextEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.serve()
+
+	channel := NewSMTPChannel(server.config())
+	err := channel.Send(&models.NotificationMessage{
+		Type:    models.NotificationDueSoon,
+		To:      "patron@example.org",
+		Name:    "Ada Patron",
+		Subject: "Your loan is due soon",
+		Body:    "Hello Ada,\nplease return your book.\n.\nThanks",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	session := server.session(t)
+	if session.from != "library@example.org" {
+		t.Errorf("MAIL FROM = %q, want %q", session.from, "library@example.org")
+	}
+	if len(session.recipients) != 1 || session.recipients[0] != "patron@example.org" {
+		t.Errorf("RCPT TO = %v, want [patron@example.org]", session.recipients)
+	}
+	if session.auth != "" {
+		t.Errorf("authenticated without a configured username")
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("message is not valid email: %v", err)
+	}
+
+	headers := map[string]string{
+		"From":         `"City Library" <library@example.org>`,
+		"To":           `"Ada Patron" <patron@example.org>`,
+		"Subject":      "Your loan is due soon",
+		"Content-Type": "text/plain; charset=utf-8",
+		"Mime-Version": "1.0",
+	}
+	for name, want := range headers {
+		if got := msg.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date header is invalid: %v", err)
+	}
+
+	body, _ := io.ReadAll(msg.Body)
+	if want := "Hello Ada,\nplease return your book.\n.\nThanks"; strings.TrimRight(string(body), "\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestSMTPChannelEncodesSubject(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.serve()
+
+	channel := NewSMTPChannel(server.config())
+	err := channel.Send(&models.NotificationMessage{
+		Type:    models.NotificationHoldReady,
+		To:      "patron@example.org",
+		Subject: "Réservation prête",
+		Body:    "Bonjour",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(server.session(t).data))
+	if err != nil {
+		t.Fatalf("message is not valid email: %v", err)
+	}
+
+	raw := msg.Header.Get("Subject")
+	if !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Errorf("Subject = %q, want a Q-encoded header", raw)
+	}
+
+	decoded, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil || decoded != "Réservation prête" {
+		t.Errorf("decoded Subject = %q (%v), want %q", decoded, err, "Réservation prête")
+	}
+}
+
+func TestSMTPChannelAuthenticatesWithConfiguredUser(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.authPlain = true
+	server.serve()
+
+	cfg := server.config()
+	cfg.SMTPUsername = "mailer"
+	cfg.SMTPPassword = "s3cret"
+
+	channel := NewSMTPChannel(cfg)
+	err := channel.Send(&models.NotificationMessage{
+		Type:    models.NotificationDueSoon,
+		To:      "patron@example.org",
+		Subject: "Reminder",
+		Body:    "Hello",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	credentials, err := base64.StdEncoding.DecodeString(server.session(t).auth)
+	if err != nil {
+		t.Fatalf("AUTH PLAIN payload is not base64: %v", err)
+	}
+	if want := "\x00mailer\x00s3cret"; string(credentials) != want {
+		t.Errorf("AUTH PLAIN credentials = %q, want %q", credentials, want)
+	}
+}
+
+func TestSMTPChannelReportsRejectedRecipient(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.reject["gone@example.org"] = true
+	server.serve()
+
+	channel := NewSMTPChannel(server.config())
+	err := channel.Send(&models.NotificationMessage{
+		Type:    models.NotificationDueSoon,
+		To:      "gone@example.org",
+		Subject: "Reminder",
+		Body:    "Hello",
+	})
+	if err == nil {
+		t.Fatal("Send() succeeded for a rejected recipient")
+	}
+
+	if session := server.session(t); session.data != "" {
+		t.Errorf("message data sent after the recipient was rejected")
+	}
+}
+
+func TestSMTPChannelRequiresAddress(t *testing.T) {
+	channel := NewSMTPChannel(config.NotificationConfig{SMTPHost: "127.0.0.1", SMTPPort: 1})
+
+	err := channel.Send(&models.NotificationMessage{Type: models.NotificationDueSoon, Subject: "Reminder"})
+	if err == nil {
+		t.Fatal("Send() succeeded without an email address")
+	}
+}
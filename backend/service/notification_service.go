@@ -0,0 +1,315 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+)
+
+// NotificationService renders patron notifications and sends them on every
+// configured channel, honouring each patron's opt-out preferences
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	borrowingRepo    *repository.BorrowingRepository
+	userRepo         *repository.UserRepository
+
+	channels []NotificationChannel
+	logger   *logger.Logger
+
+	appBaseURL  string
+	dueSoonDays int
+}
+
+// NewNotificationService creates a new NotificationService instance
+func NewNotificationService(
+	notificationRepo *repository.NotificationRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	userRepo *repository.UserRepository,
+	cfg config.NotificationConfig,
+	logger *logger.Logger,
+	channels ...NotificationChannel,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		borrowingRepo:    borrowingRepo,
+		userRepo:         userRepo,
+
+		channels: channels,
+		logger:   logger,
+
+		appBaseURL:  strings.TrimRight(cfg.AppBaseURL, "/"),
+		dueSoonDays: cfg.DueSoonDays,
+	}
+}
+
+// Notify renders a notification for a user and sends it on every channel.
+// Optional notifications the user turned off are skipped silently.
+func (s *NotificationService) Notify(user *models.User, notificationType string, data map[string]interface{}) error {
+	tmpl, ok := notificationTemplates[notificationType]
+	if !ok {
+		return models.ErrUnknownNotificationType
+	}
+
+	if !models.IsMandatoryNotification(notificationType) {
+		enabled, err := s.notificationRepo.IsEnabled(user.ID, notificationType)
+		if err != nil {
+			return fmt.Errorf("failed to load notification preference: %w", err)
+		}
+		if !enabled {
+			return nil
+		}
+	}
+
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["Name"] = user.FullName
+
+	subject, body, err := tmpl.render(data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s notification: %w", notificationType, err)
+	}
+
+	message := &models.NotificationMessage{
+		Type:    notificationType,
+		To:      user.Email,
+		Name:    user.FullName,
+		Subject: subject,
+		Body:    body,
+	}
+
+	// Try every channel so one outage does not silence the others
+	var firstErr error
+	for _, channel := range s.channels {
+		if err := channel.Send(message); err != nil {
+			s.logger.Error("Failed to send notification",
+				"channel", channel.Name(), "type", notificationType, "user_id", user.ID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// SendPasswordReset emails a password reset link to a user
+func (s *NotificationService) SendPasswordReset(user *models.User, resetToken string, validFor time.Duration) error {
+	link := fmt.Sprintf("%s/reset-password?email=%s&token=%s",
+		s.appBaseURL, url.QueryEscape(user.Email), url.QueryEscape(resetToken))
+
+	return s.Notify(user, models.NotificationPasswordReset, map[string]interface{}{
+		"Link":     link,
+		"ValidFor": formatDuration(validFor),
+	})
+}
+
+// SendEmailVerification emails a link that confirms a new patron's address
+func (s *NotificationService) SendEmailVerification(user *models.User, token string, validFor time.Duration, requiresApproval bool) error {
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.appBaseURL, url.QueryEscape(token))
+
+	return s.Notify(user, models.NotificationVerifyEmail, map[string]interface{}{
+		"Link":             link,
+		"ValidFor":         formatDuration(validFor),
+		"RequiresApproval": requiresApproval,
+	})
+}
+
+// NotifyAccountApproved tells a patron that staff approved their registration
+func (s *NotificationService) NotifyAccountApproved(user *models.User) error {
+	return s.Notify(user, models.NotificationAccountApproved, map[string]interface{}{
+		"Link": s.appBaseURL + "/login",
+	})
+}
+
+// NotifyTwoFactorChanged tells a user that two-factor authentication was
+// turned on or off for their account
+func (s *NotificationService) NotifyTwoFactorChanged(user *models.User, enabled bool) error {
+	return s.Notify(user, models.NotificationTwoFactorChanged, map[string]interface{}{
+		"Enabled": enabled,
+	})
+}
+
+// NotifySessionRevoked tells a user that a session was signed out because
+// its refresh token was reused
+func (s *NotificationService) NotifySessionRevoked(user *models.User, device, ipAddress string) error {
+	return s.Notify(user, models.NotificationSessionRevoked, map[string]interface{}{
+		"Device":    device,
+		"IPAddress": ipAddress,
+	})
+}
+
+// NotifyHoldReady tells a patron that a copy has been set aside for their
+// hold. Delivery failures are logged since the hold itself is already saved.
+func (s *NotificationService) NotifyHoldReady(reservation *models.Reservation) {
+	user, err := s.userRepo.GetByID(reservation.UserID)
+	if err != nil {
+		s.logger.Error("Failed to load patron for hold notice", "reservation_id", reservation.ID, "error", err)
+		return
+	}
+
+	pickupBy := ""
+	if reservation.PickupExpiresAt != nil {
+		pickupBy = reservation.PickupExpiresAt.Format(dateLayout)
+	}
+
+	err = s.Notify(user, models.NotificationHoldReady, map[string]interface{}{
+		"Title":    reservation.BookTitle,
+		"PickupBy": pickupBy,
+	})
+	if err != nil {
+		s.logger.Error("Failed to send hold notice", "reservation_id", reservation.ID, "error", err)
+	}
+}
+
+// SendDueSoonReminders reminds patrons of loans due within the configured
+// number of days. Each due date of a loan is only reminded about once.
+func (s *NotificationService) SendDueSoonReminders(now time.Time) (int, error) {
+	borrowings, err := s.borrowingRepo.ListDueBetween(now, now.AddDate(0, 0, s.dueSoonDays))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list loans due soon: %w", err)
+	}
+
+	sent := 0
+	for _, borrowing := range borrowings {
+		// A renewed loan gets a fresh reminder for its new due date
+		reference := fmt.Sprintf("%d:%s", borrowing.ID, borrowing.DueDate.Format(dateLayout))
+
+		ok, err := s.notifyLoan(borrowing, models.NotificationDueSoon, reference)
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// SendOverdueNotices tells patrons once that a loan has become overdue
+func (s *NotificationService) SendOverdueNotices(now time.Time) (int, error) {
+	borrowings, err := s.borrowingRepo.ListUnreturnedOverdue(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list overdue loans: %w", err)
+	}
+
+	sent := 0
+	for _, borrowing := range borrowings {
+		ok, err := s.notifyLoan(borrowing, models.NotificationOverdue, fmt.Sprintf("%d", borrowing.ID))
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// ListPreferences returns every notification type with the user's choice.
+// Patrons may only see their own preferences.
+func (s *NotificationService) ListPreferences(userID, actorID int64) ([]*models.NotificationPreference, error) {
+	if err := s.checkSelfOrStaff(userID, actorID); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.notificationRepo.ListPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]*models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		preference := &models.NotificationPreference{Type: notificationType, Enabled: true}
+		if existing, ok := saved[notificationType]; ok {
+			preference = existing
+		}
+
+		preference.Mandatory = models.IsMandatoryNotification(notificationType)
+		if preference.Mandatory {
+			preference.Enabled = true
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+// UpdatePreference turns a type of notification on or off for a user
+func (s *NotificationService) UpdatePreference(userID int64, notificationType string, enabled bool, actorID int64) error {
+	if err := s.checkSelfOrStaff(userID, actorID); err != nil {
+		return err
+	}
+
+	if !models.IsNotificationType(notificationType) {
+		return models.ErrUnknownNotificationType
+	}
+	if models.IsMandatoryNotification(notificationType) && !enabled {
+		return models.ErrNotificationRequired
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	return s.notificationRepo.SetPreference(userID, notificationType, enabled)
+}
+
+// notifyLoan sends a loan notice unless one already went out for reference.
+// It reports whether a notice was sent.
+func (s *NotificationService) notifyLoan(borrowing *models.Borrowing, notificationType, reference string) (bool, error) {
+	alreadySent, err := s.notificationRepo.WasSent(notificationType, reference)
+	if err != nil {
+		return false, fmt.Errorf("failed to check notification log: %w", err)
+	}
+	if alreadySent {
+		return false, nil
+	}
+
+	user, err := s.userRepo.GetByID(borrowing.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load patron %d: %w", borrowing.UserID, err)
+	}
+
+	err = s.Notify(user, notificationType, map[string]interface{}{
+		"Title":   borrowing.BookTitle,
+		"DueDate": borrowing.DueDate.Format(dateLayout),
+	})
+	if err != nil {
+		// Leave it unrecorded so the next run tries again
+		return false, nil
+	}
+
+	if err := s.notificationRepo.RecordSent(user.ID, notificationType, reference); err != nil {
+		return true, fmt.Errorf("failed to record notification: %w", err)
+	}
+
+	return true, nil
+}
+
+// checkSelfOrStaff makes sure the acting user may manage a user's preferences
+func (s *NotificationService) checkSelfOrStaff(userID, actorID int64) error {
+	return requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage)
+}
+
+// formatDuration describes a duration in whole hours or minutes for messages
+func formatDuration(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		hours := int(d / time.Hour)
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+
+	return fmt.Sprintf("%d minutes", int(d/time.Minute))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"strings"
+	"text/template"
+
+	"library-management-system/internal/models"
+)
+
+// notificationTemplate holds the subject and body templates of a notification
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// newNotificationTemplate parses a subject and body template, panicking on
+// syntax errors since the templates are compiled into the binary
+func newNotificationTemplate(name, subject, body string) *notificationTemplate {
+	return &notificationTemplate{
+		subject: template.Must(template.New(name + "_subject").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Parse(strings.TrimLeft(body, "\n"))),
+	}
+}
+
+// render executes both templates with the given data
+func (t *notificationTemplate) render(data interface{}) (string, string, error) {
+	var subject, body strings.Builder
+
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return subject.String(), body.String(), nil
+}
+
+// notificationTemplates maps each notification type to its message
+var notificationTemplates = map[string]*notificationTemplate{
+	models.NotificationDueSoon: newNotificationTemplate(
+		models.NotificationDueSoon,
+		`Reminder: "{{.Title}}" is due on {{.DueDate}}`,
+		`
+Hello {{.Name}},
+
+"{{.Title}}" is due back on {{.DueDate}}. You can renew it from your
+account if nobody else is waiting for it.
+
+Your library
+`),
+
+	models.NotificationOverdue: newNotificationTemplate(
+		models.NotificationOverdue,
+		`Overdue: "{{.Title}}"`,
+		`
+Hello {{.Name}},
+
+"{{.Title}}" was due back on {{.DueDate}} and is now overdue. Fines
+accrue for every day the library is open until it is returned.
+
+Your library
+`),
+
+	models.NotificationHoldReady: newNotificationTemplate(
+		models.NotificationHoldReady,
+		`Your hold on "{{.Title}}" is ready for pickup`,
+		`
+Hello {{.Name}},
+
+A copy of "{{.Title}}" is waiting for you at the front desk. It will be
+held for you until {{.PickupBy}}.
+
+Your library
+`),
+
+	models.NotificationPasswordReset: newNotificationTemplate(
+		models.NotificationPasswordReset,
+		`Reset your library password`,
+		`
+Hello {{.Name}},
+
+We received a request to reset your password. Use the link below within
+{{.ValidFor}} to choose a new one:
+
+{{.Link}}
+
+If you did not ask for this, you can ignore this email.
+
+Your library
+`),
+
+	models.NotificationTwoFactorChanged: newNotificationTemplate(
+		models.NotificationTwoFactorChanged,
+		`Two-factor authentication was {{if .Enabled}}enabled{{else}}disabled{{end}}`,
+		`
+Hello {{.Name}},
+
+Two-factor authentication was {{if .Enabled}}enabled{{else}}disabled{{end}} on your library
+account. If you did not make this change, reset your password and contact
+the library immediately.
+
+Your library
+`),
+
+	models.NotificationSessionRevoked: newNotificationTemplate(
+		models.NotificationSessionRevoked,
+		`We signed out one of your sessions`,
+		`
+Hello {{.Name}},
+
+A sign-in token for your library account on {{.Device}} was used again after
+it had been replaced, which can mean it was copied. We signed that session
+out to protect your account. The request came from {{.IPAddress}}.
+
+If this was not you, change your password.
+
+Your library
+`),
+
+	models.NotificationVerifyEmail: newNotificationTemplate(
+		models.NotificationVerifyEmail,
+		`Confirm your email address`,
+		`
+Hello {{.Name}},
+
+Thanks for registering with the library. Use the link below within
+{{.ValidFor}} to confirm your email address:
+
+{{.Link}}
+
+{{if .RequiresApproval}}Library staff will review your registration once your address is confirmed.
+{{end}}If you did not register, you can ignore this email.
+
+Your library
+`),
+
+	models.NotificationAccountApproved: newNotificationTemplate(
+		models.NotificationAccountApproved,
+		`Your library account is ready`,
+		`
+Hello {{.Name}},
+
+Your registration has been approved. You can now sign in, borrow books
+and place holds:
+
+{{.Link}}
+
+Your library
+`),
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	mockClientID     = "library-app"
+	mockClientSecret = "client-secret"
+	mockRedirectURL  = "http://localhost:3000/auth/callback"
+)
+
+// mockAuthRequest is an authorization request the mock provider has answered
+// with a code
+type mockAuthRequest struct {
+	challenge string
+	nonce     string
+}
+
+// mockIdP is a local OpenID Connect provider. It serves discovery, a JWKS,
+// an authorization endpoint that immediately redirects back with a code, and
+// a token endpoint that enforces PKCE and signs ID tokens with an RSA key.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	keyID  string
+
+	mu            sync.Mutex
+	issuer        string
+	codes         int
+	requests      map[string]mockAuthRequest
+	claims        jwt.MapClaims
+	audience      string
+	nonce         string
+	discoveryHits int
+	jwksHits      int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	idp := &mockIdP{
+		key:      key,
+		keyID:    "mock-key-1",
+		requests: make(map[string]mockAuthRequest),
+		claims:   jwt.MapClaims{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// provider returns a client for the mock provider
+func (m *mockIdP) provider() *oidcProvider {
+	return newOIDCProvider(m.server.URL, mockClientID, mockClientSecret, mockRedirectURL, m.server.Client())
+}
+
+// setClaims replaces the extra claims put in the next ID tokens
+func (m *mockIdP) setClaims(claims jwt.MapClaims) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claims = claims
+}
+
+func (m *mockIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.discoveryHits++
+	issuer := m.issuer
+	m.mu.Unlock()
+
+	if issuer == "" {
+		issuer = m.server.URL
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 issuer,
+		"authorization_endpoint": m.server.URL + "/authorize",
+		"token_endpoint":         m.server.URL + "/token",
+		"jwks_uri":               m.server.URL + "/jwks",
+	})
+}
+
+func (m *mockIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.jwksHits++
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": m.keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != mockClientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	m.codes++
+	code := fmt.Sprintf("code-%d", m.codes)
+	m.requests[code] = mockAuthRequest{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+
+	redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (m *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if id, secret, ok := r.BasicAuth(); !ok || id != mockClientID || secret != mockClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Codes are single use
+	code := r.PostForm.Get("code")
+	request, ok := m.requests[code]
+	delete(m.requests, code)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown code"})
+		return
+	}
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifier[:]) != request.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   m.server.URL,
+		"aud":   mockClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": request.nonce,
+	}
+	if m.audience != "" {
+		claims["aud"] = m.audience
+	}
+	if m.nonce != "" {
+		claims["nonce"] = m.nonce
+	}
+	for name, value := range m.claims {
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.keyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+// signIn plays the browser: it follows an authorization URL to the mock
+// provider and returns the code and state from the redirect back
+func (m *mockIdP) signIn(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorization request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect: %v", err)
+	}
+	if !strings.HasPrefix(location.String(), mockRedirectURL+"?") {
+		t.Fatalf("redirected to %q, want %q", location, mockRedirectURL)
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// pkcePair returns a verifier and its S256 challenge
+func pkcePair(t *testing.T) (verifier, challenge string) {
+	t.Helper()
+
+	verifier, err := randomURLString(32)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestOIDCProviderAuthorizationURL(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := idp.provider()
+
+	authURL, err := provider.authorizationURL("state-1", "nonce-1", "challenge-1", []string{"openid", "email"})
+	if err != nil {
+		t.Fatalf("authorizationURL() error = %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL: %v", err)
+	}
+	if got := parsed.Scheme + "://" + parsed.Host + parsed.Path; got != idp.server.URL+"/authorize" {
+		t.Errorf("authorization endpoint = %q, want the discovered one", got)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             mockClientID,
+		"redirect_uri":          mockRedirectURL,
+		"scope":                 "openid email",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := parsed.Query().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestOIDCProviderExchangeVerifiesIDToken(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.setClaims(jwt.MapClaims{"sub": "campus-42", "email": "ada@example.edu", "email_verified": true})
+	provider := idp.provider()
+
+	for i := 0; i < 2; i++ {
+		verifier, challenge := pkcePair(t)
+		authURL, err := provider.authorizationURL("state", "nonce-abc", challenge, []string{"openid"})
+		if err != nil {
+			t.Fatalf("authorizationURL() error = %v", err)
+		}
+		code, state := idp.signIn(t, authURL)
+		if state != "state" {
+			t.Errorf("state = %q, want %q", state, "state")
+		}
+
+		claims, err := provider.exchange(code, verifier, "nonce-abc")
+		if err != nil {
+			t.Fatalf("exchange() error = %v", err)
+		}
+		if claims["sub"] != "campus-42" || claims["email"] != "ada@example.edu" {
+			t.Errorf("claims = %v, want the provider's subject and email", claims)
+		}
+	}
+
+	// Discovery and keys are cached across sign-ins
+	if idp.discoveryHits != 1 || idp.jwksHits != 1 {
+		t.Errorf("discovery fetched %d times and keys %d times, want once each", idp.discoveryHits, idp.jwksHits)
+	}
+}
+
+func TestOIDCProviderRejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.setClaims(jwt.MapClaims{"sub": "campus-42"})
+	provider := idp.provider()
+
+	_, challenge := pkcePair(t)
+	authURL, err := provider.authorizationURL("state", "nonce", challenge, []string{"openid"})
+	if err != nil {
+		t.Fatalf("authorizationURL() error = %v", err)
+	}
+	code, _ := idp.signIn(t, authURL)
+
+	otherVerifier, _ := pkcePair(t)
+	_, err = provider.exchange(code, otherVerifier, "nonce")
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("exchange() error = %v, want invalid_grant", err)
+	}
+}
+
+func TestOIDCProviderRejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		audience string
+		nonce    string
+		want     string
+	}{
+		{name: "nonce mismatch", nonce: "someone-elses-nonce", want: "nonce mismatch"},
+		{name: "wrong audience", audience: "another-app", want: "audience"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			idp.setClaims(jwt.MapClaims{"sub": "campus-42"})
+			idp.audience = tt.audience
+			idp.nonce = tt.nonce
+			provider := idp.provider()
+
+			verifier, challenge := pkcePair(t)
+			authURL, err := provider.authorizationURL("state", "nonce", challenge, []string{"openid"})
+			if err != nil {
+				t.Fatalf("authorizationURL() error = %v", err)
+			}
+			code, _ := idp.signIn(t, authURL)
+
+			_, err = provider.exchange(code, verifier, "nonce")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("exchange() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCProviderRejectsUnknownSigningKey(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := idp.provider()
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   idp.server.URL,
+		"aud":   mockClientID,
+		"sub":   "campus-42",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": "nonce",
+	})
+	token.Header["kid"] = "forged-key"
+	raw, err := token.SignedString(other)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err := provider.verifyIDToken(raw, "nonce"); err == nil {
+		t.Fatal("verifyIDToken() accepted a token signed with an unknown key")
+	}
+}
+
+func TestOIDCProviderRejectsMismatchedIssuer(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.issuer = "https://login.example.com"
+	provider := idp.provider()
+
+	_, err := provider.authorizationURL("state", "nonce", "challenge", []string{"openid"})
+	if err == nil || !strings.Contains(err.Error(), "issuer") {
+		t.Fatalf("authorizationURL() error = %v, want an issuer mismatch", err)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+)
+
+// Outbox retry timing
+const (
+	outboxRetryBase = 10 * time.Second
+	outboxRetryMax  = time.Hour
+)
+
+// EventSubscriber receives domain events from the outbox. Events are
+// delivered at least once, so Handle must tolerate duplicates.
+type EventSubscriber interface {
+	// Name identifies the subscriber; it must stay stable across restarts
+	Name() string
+
+	// Handle processes one event. Returning an error schedules a retry.
+	Handle(event *models.OutboxEvent) error
+}
+
+// OutboxDispatcher polls the outbox and hands every event to each subscriber
+type OutboxDispatcher struct {
+	outboxRepo *repository.OutboxRepository
+	logger     *logger.Logger
+
+	interval  time.Duration
+	batchSize int
+
+	mu          sync.RWMutex
+	subscribers []EventSubscriber
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewOutboxDispatcher creates a new OutboxDispatcher instance
+func NewOutboxDispatcher(
+	outboxRepo *repository.OutboxRepository,
+	logger *logger.Logger,
+	interval time.Duration,
+	batchSize int,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo: outboxRepo,
+		logger:     logger,
+
+		interval:  interval,
+		batchSize: batchSize,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Subscribe adds a subscriber that receives every future delivery attempt
+func (d *OutboxDispatcher) Subscribe(subscriber EventSubscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribers = append(d.subscribers, subscriber)
+}
+
+// Start dispatches pending events immediately and then on every interval
+// until Stop is called
+func (d *OutboxDispatcher) Start() {
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := d.Dispatch(time.Now()); err != nil {
+				d.logger.Error("Outbox dispatch failed", "error", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the dispatcher to finish and waits for the current batch to end
+func (d *OutboxDispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	<-d.done
+}
+
+// Dispatch delivers one batch of pending events and returns how many were
+// fully delivered. Failed events are retried later with exponential backoff.
+func (d *OutboxDispatcher) Dispatch(now time.Time) (int, error) {
+	events, err := d.outboxRepo.ListPending(now, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending events: %w", err)
+	}
+
+	d.mu.RLock()
+	subscribers := append([]EventSubscriber(nil), d.subscribers...)
+	d.mu.RUnlock()
+
+	dispatched := 0
+	for _, event := range events {
+		if err := d.deliver(event, subscribers); err != nil {
+			d.logger.Error("Failed to deliver event",
+				"event_id", event.ID, "event_type", event.EventType, "attempt", event.Attempts+1, "error", err)
+
+			if err := d.outboxRepo.MarkFailed(event.ID, err.Error(), now.Add(outboxBackoff(event.Attempts))); err != nil {
+				return dispatched, fmt.Errorf("failed to record failed delivery: %w", err)
+			}
+			continue
+		}
+
+		if err := d.outboxRepo.MarkDispatched(event.ID); err != nil {
+			return dispatched, fmt.Errorf("failed to mark event dispatched: %w", err)
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// deliver hands an event to every subscriber that has not handled it yet
+func (d *OutboxDispatcher) deliver(event *models.OutboxEvent, subscribers []EventSubscriber) error {
+	delivered, err := d.outboxRepo.DeliveredTo(event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load deliveries: %w", err)
+	}
+
+	var firstErr error
+	for _, subscriber := range subscribers {
+		if delivered[subscriber.Name()] {
+			continue
+		}
+
+		if err := subscriber.Handle(event); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", subscriber.Name(), err)
+			}
+			continue
+		}
+
+		if err := d.outboxRepo.RecordDelivery(event.ID, subscriber.Name()); err != nil {
+			return fmt.Errorf("failed to record delivery: %w", err)
+		}
+	}
+
+	return firstErr
+}
+
+// outboxBackoff returns how long to wait before retrying an event that has
+// already failed the given number of times
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 0; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+
+	if delay > outboxRetryMax {
+		return outboxRetryMax
+	}
+	return delay
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+)
+
+// OverdueSweeper periodically moves loans through their overdue lifecycle.
+// Each sweep marks past-due loans overdue, accrues their fines, flags loans
+// that stayed out too long as presumed lost, sends due-soon reminders and
+// overdue notices and expires stale holds.
+type OverdueSweeper struct {
+	db            *repository.Database
+	borrowingRepo *repository.BorrowingRepository
+	copyRepo      *repository.BookCopyRepository
+	policyRepo    *repository.CirculationPolicyRepository
+	fineRepo      *repository.FineRepository
+	calendarRepo  *repository.LibraryCalendarRepository
+	activityRepo  *repository.ActivityLogRepository
+
+	reservationService  *ReservationService
+	notificationService *NotificationService
+	logger              *logger.Logger
+
+	interval      time.Duration
+	lostAfterDays int
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewOverdueSweeper creates a new OverdueSweeper instance
+func NewOverdueSweeper(
+	db *repository.Database,
+	borrowingRepo *repository.BorrowingRepository,
+	copyRepo *repository.BookCopyRepository,
+	policyRepo *repository.CirculationPolicyRepository,
+	fineRepo *repository.FineRepository,
+	calendarRepo *repository.LibraryCalendarRepository,
+	activityRepo *repository.ActivityLogRepository,
+	reservationService *ReservationService,
+	notificationService *NotificationService,
+	logger *logger.Logger,
+	interval time.Duration,
+	lostAfterDays int,
+) *OverdueSweeper {
+	return &OverdueSweeper{
+		db:            db,
+		borrowingRepo: borrowingRepo,
+		copyRepo:      copyRepo,
+		policyRepo:    policyRepo,
+		fineRepo:      fineRepo,
+		calendarRepo:  calendarRepo,
+		activityRepo:  activityRepo,
+
+		reservationService:  reservationService,
+		notificationService: notificationService,
+		logger:              logger,
+
+		interval:      interval,
+		lostAfterDays: lostAfterDays,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Start runs a sweep immediately and then on every interval until Stop is called
+func (s *OverdueSweeper) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run()
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the sweeper to finish and waits for the current sweep to end
+func (s *OverdueSweeper) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// run performs one sweep and logs the outcome
+func (s *OverdueSweeper) run() {
+	result, err := s.Sweep(time.Now())
+	if err != nil {
+		s.logger.Error("Overdue sweep failed", "error", err)
+	}
+
+	s.logger.Info("Overdue sweep finished",
+		"overdue", result.MarkedOverdue,
+		"fines_accrued", result.FinesAccrued,
+		"presumed_lost", result.PresumedLost,
+		"reminders_sent", result.RemindersSent,
+		"overdue_notices_sent", result.OverdueNoticesSent,
+		"holds_expired", result.HoldsExpired,
+	)
+}
+
+// SweepResult counts what a single sweep changed
+type SweepResult struct {
+	MarkedOverdue      int
+	FinesAccrued       int
+	PresumedLost       int
+	RemindersSent      int
+	OverdueNoticesSent int
+	HoldsExpired       int
+}
+
+// Sweep brings every open loan up to date as of now. A failure on one loan
+// does not stop the others; the first error is returned once all are done.
+func (s *OverdueSweeper) Sweep(now time.Time) (SweepResult, error) {
+	var result SweepResult
+	var firstErr error
+
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	marked, err := s.borrowingRepo.MarkOverdue(now)
+	if err != nil {
+		record(fmt.Errorf("failed to mark overdue loans: %w", err))
+	}
+	result.MarkedOverdue = int(marked)
+
+	overdue, err := s.borrowingRepo.ListUnreturnedOverdue(now)
+	if err != nil {
+		record(fmt.Errorf("failed to list overdue loans: %w", err))
+	}
+
+	lostCutoff := now.AddDate(0, 0, -s.lostAfterDays)
+	for _, borrowing := range overdue {
+		accrued, err := s.accrueFine(borrowing, now)
+		if err != nil {
+			record(fmt.Errorf("failed to accrue fine for borrowing %d: %w", borrowing.ID, err))
+			continue
+		}
+		if accrued {
+			result.FinesAccrued++
+		}
+
+		if s.lostAfterDays <= 0 || !borrowing.DueDate.Before(lostCutoff) {
+			continue
+		}
+
+		if err := s.markPresumedLost(borrowing); err != nil {
+			record(fmt.Errorf("failed to mark borrowing %d lost: %w", borrowing.ID, err))
+			continue
+		}
+		result.PresumedLost++
+	}
+
+	reminders, err := s.notificationService.SendDueSoonReminders(now)
+	if err != nil {
+		record(err)
+	}
+	result.RemindersSent = reminders
+
+	notices, err := s.notificationService.SendOverdueNotices(now)
+	if err != nil {
+		record(err)
+	}
+	result.OverdueNoticesSent = notices
+
+	expired, err := s.reservationService.ExpireHolds(now)
+	if err != nil {
+		record(err)
+	}
+	result.HoldsExpired = expired
+
+	return result, firstErr
+}
+
+// accrueFine recalculates the running fine on an overdue loan. The fine only
+// changes once per calendar day, so an assessment is recorded whenever it does.
+func (s *OverdueSweeper) accrueFine(borrowing *models.Borrowing, now time.Time) (bool, error) {
+	accrued := false
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		// The loan may have been returned since it was listed
+		if _, _, err := s.borrowingRepo.LockOpenLoan(tx, borrowing.ID); err != nil {
+			return err
+		}
+
+		policy, err := s.policyRepo.ResolveForBorrowing(tx, borrowing.ID)
+		if err != nil {
+			return err
+		}
+
+		closedDays, err := s.calendarRepo.ListClosedDays(tx, borrowing.DueDate, now)
+		if err != nil {
+			return fmt.Errorf("failed to load closed days: %w", err)
+		}
+
+		assessment := assessFine(policy, borrowing, now, closedDays)
+		if assessment.Amount == borrowing.FineAmount {
+			return nil
+		}
+
+		if err := s.borrowingRepo.UpdateFineAmount(tx, borrowing.ID, assessment.Amount); err != nil {
+			return err
+		}
+
+		accrued = true
+		if err := s.fineRepo.CreateAssessment(tx, assessment); err != nil {
+			return err
+		}
+
+		// The sweeper acts on its own, so the trail has no user
+		err = recordChange(s.activityRepo, tx, models.Actor{}, models.ActivityCreate, models.EntityFine, assessment.ID, nil, assessment)
+		if err != nil {
+			return err
+		}
+
+		after := *borrowing
+		after.FineAmount = assessment.Amount
+		return recordChange(s.activityRepo, tx, models.Actor{}, models.ActivityUpdate, models.EntityBorrowing, borrowing.ID, borrowing, &after)
+	})
+	if errors.Is(err, models.ErrBorrowingAlreadyReturned) {
+		return false, nil
+	}
+
+	return accrued, err
+}
+
+// markPresumedLost flags a long-overdue loan and its copy as lost. The loan
+// stays open so the copy can still be checked back in if it turns up.
+func (s *OverdueSweeper) markPresumedLost(borrowing *models.Borrowing) error {
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		if _, _, err := s.borrowingRepo.LockOpenLoan(tx, borrowing.ID); err != nil {
+			return err
+		}
+
+		if err := s.borrowingRepo.MarkLost(tx, borrowing.ID); err != nil {
+			return err
+		}
+
+		lost := *borrowing
+		lost.Status = models.BorrowingStatusLost
+		err := recordChange(s.activityRepo, tx, models.Actor{}, models.ActivityUpdate, models.EntityBorrowing, borrowing.ID, borrowing, &lost)
+		if err != nil {
+			return err
+		}
+
+		if err := s.borrowingRepo.UpdateBookCopyStatus(tx, borrowing.BookCopyID, models.BookCopyStatusLost); err != nil {
+			return err
+		}
+
+		// Lost copies no longer count towards the book's holdings
+		return s.copyRepo.SyncBookCounts(tx, borrowing.BookID)
+	})
+	if errors.Is(err, models.ErrBorrowingAlreadyReturned) {
+		return nil
+	}
+
+	return err
+}
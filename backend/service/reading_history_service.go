@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+)
+
+// ReadingHistoryService manages whether patrons keep their reading history
+// and periodically unlinks returned loans from patrons who do not
+type ReadingHistoryService struct {
+	userRepo      *repository.UserRepository
+	borrowingRepo *repository.BorrowingRepository
+	logger        *logger.Logger
+	config        config.ReadingHistoryConfig
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewReadingHistoryService creates a new ReadingHistoryService instance
+func NewReadingHistoryService(
+	userRepo *repository.UserRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	logger *logger.Logger,
+	cfg config.ReadingHistoryConfig,
+) *ReadingHistoryService {
+	return &ReadingHistoryService{
+		userRepo:      userRepo,
+		borrowingRepo: borrowingRepo,
+		logger:        logger,
+		config:        cfg,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// GetPreference returns whether a patron keeps their reading history
+func (s *ReadingHistoryService) GetPreference(userID, actorID int64) (*models.ReadingHistoryPreference, error) {
+	if err := requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	keep, err := s.userRepo.GetReadingHistoryPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.preference(userID, keep), nil
+}
+
+// SetPreference opts a patron in to or out of keeping their reading history.
+// A nil keep returns them to the library default. Opting out does not
+// unlink loans returned within the retention window straight away.
+func (s *ReadingHistoryService) SetPreference(userID, actorID int64, keep *bool) (*models.ReadingHistoryPreference, error) {
+	if err := requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetReadingHistoryPreference(userID, keep); err != nil {
+		return nil, err
+	}
+
+	return s.preference(userID, keep), nil
+}
+
+// preference resolves a stored choice against the library default
+func (s *ReadingHistoryService) preference(userID int64, keep *bool) *models.ReadingHistoryPreference {
+	preference := &models.ReadingHistoryPreference{
+		UserID:         userID,
+		KeepHistory:    s.config.KeepByDefault,
+		LibraryDefault: keep == nil,
+		RetentionDays:  s.config.RetentionDays,
+	}
+	if keep != nil {
+		preference.KeepHistory = *keep
+	}
+	return preference
+}
+
+// Start runs a purge immediately and then on every interval until Stop is called
+func (s *ReadingHistoryService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(time.Duration(s.config.PurgeIntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			purged, err := s.Purge(time.Now())
+			if err != nil {
+				s.logger.Error("Reading history purge failed", "error", err)
+			}
+			if purged > 0 {
+				s.logger.Info("Reading history purged", "loans", purged)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the purge to finish and waits for the current run to end
+func (s *ReadingHistoryService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// Purge unlinks returned loans that have passed the retention window from
+// patrons who do not keep their reading history, one batch per call. A
+// failure on one loan does not stop the others; the first error is returned
+// once all are done.
+func (s *ReadingHistoryService) Purge(now time.Time) (int, error) {
+	cutoff := now.AddDate(0, 0, -s.config.RetentionDays)
+
+	loans, err := s.borrowingRepo.ListExpiredHistory(cutoff, s.config.KeepByDefault, s.config.PurgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired reading history: %w", err)
+	}
+
+	purged := 0
+	var firstErr error
+	for _, loan := range loans {
+		if err := s.borrowingRepo.UnlinkPatron(loan.ID, loan.UserID); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to unlink borrowing %d: %w", loan.ID, err)
+			}
+			continue
+		}
+		purged++
+	}
+
+	return purged, firstErr
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPasswordLength is the shortest password a patron may register with
+const minPasswordLength = 8
+
+// expiryBatchSize caps how many unverified accounts one expiry run removes
+const expiryBatchSize = 100
+
+// RegistrationService lets patrons sign up themselves. New accounts stay
+// pending until their email is verified and, when the library requires it,
+// staff approve them.
+type RegistrationService struct {
+	db                  *repository.Database
+	userRepo            *repository.UserRepository
+	registrationRepo    *repository.RegistrationRepository
+	authLogRepo         *repository.AuthLogRepository
+	activityRepo        *repository.ActivityLogRepository
+	notificationService *NotificationService
+	logger              *logger.Logger
+	config              config.RegistrationConfig
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewRegistrationService creates a new RegistrationService instance
+func NewRegistrationService(
+	db *repository.Database,
+	userRepo *repository.UserRepository,
+	registrationRepo *repository.RegistrationRepository,
+	authLogRepo *repository.AuthLogRepository,
+	activityRepo *repository.ActivityLogRepository,
+	notificationService *NotificationService,
+	logger *logger.Logger,
+	cfg config.RegistrationConfig,
+) *RegistrationService {
+	return &RegistrationService{
+		db:                  db,
+		userRepo:            userRepo,
+		registrationRepo:    registrationRepo,
+		authLogRepo:         authLogRepo,
+		activityRepo:        activityRepo,
+		notificationService: notificationService,
+		logger:              logger,
+		config:              cfg,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Register creates a pending member account and emails a verification link.
+// Emails and phone numbers already used by another account are refused. A
+// pending account whose email was never verified does not hold the address,
+// so it is replaced rather than letting it lock out the address's owner.
+func (s *RegistrationService) Register(registration models.Registration, actor models.Actor, userAgent string) (*models.RegistrationStatus, error) {
+	if !s.config.Enabled {
+		return nil, models.ErrRegistrationDisabled
+	}
+
+	email := strings.ToLower(strings.TrimSpace(registration.Email))
+	fullName := strings.TrimSpace(registration.FullName)
+	if email == "" || fullName == "" || len(registration.Password) < minPasswordLength {
+		return nil, models.ErrInvalidRegistration
+	}
+
+	phone := strings.TrimSpace(registration.Phone)
+	digits := phoneDigits(phone)
+	if phone != "" && (len(digits) < 7 || len(digits) > 15) {
+		return nil, models.ErrInvalidPhone
+	}
+
+	existing, err := s.userRepo.GetByEmail(email)
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if existing != nil {
+		var replaced bool
+		err := s.db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			replaced, err = s.discardUnverified(tx, existing.ID, actor)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to replace unverified account: %w", err)
+		}
+		if !replaced {
+			return nil, models.ErrEmailTaken
+		}
+	}
+
+	if phone != "" {
+		inUse, err := s.userRepo.PhoneInUse(digits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check phone: %w", err)
+		}
+		if inUse {
+			return nil, models.ErrPhoneTaken
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(registration.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Email:         email,
+		PasswordHash:  string(hash),
+		FullName:      fullName,
+		Role:          models.UserRoleMember,
+		Phone:         phone,
+		Address:       strings.TrimSpace(registration.Address),
+		AccountStatus: models.UserStatusPending,
+	}
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.Create(tx, user); err != nil {
+			return fmt.Errorf("failed to create account: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityUser, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionRegister,
+		IPAddress: actor.IPAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
+
+	// The account exists either way; the patron can ask for another link
+	if err := s.sendVerification(user); err != nil {
+		s.logger.Error("Failed to send verification email", "user_id", user.ID, "error", err)
+	}
+
+	return s.status(user, false), nil
+}
+
+// ResendVerification emails a new verification link. Unknown and already
+// verified addresses are ignored, so the response does not reveal which
+// emails are registered.
+func (s *RegistrationService) ResendVerification(email string) error {
+	if !s.config.Enabled {
+		return models.ErrRegistrationDisabled
+	}
+
+	user, err := s.userRepo.GetByEmail(strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if user.AccountStatus != models.UserStatusPending {
+		return nil
+	}
+
+	verified, err := s.userRepo.IsEmailVerified(nil, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check verification: %w", err)
+	}
+	if verified {
+		return nil
+	}
+
+	return s.sendVerification(user)
+}
+
+// VerifyEmail confirms a patron's address with the token from their link.
+// Without an approval step the account becomes active straight away.
+func (s *RegistrationService) VerifyEmail(token string, actor models.Actor, userAgent string) (*models.RegistrationStatus, error) {
+	var user *models.User
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		userID, err := s.registrationRepo.ConsumeVerificationToken(tx, hashVerificationToken(token), time.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.LockForUpdate(tx, userID); err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.GetByID(userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.MarkEmailVerified(tx, userID); err != nil {
+			return fmt.Errorf("failed to verify email: %w", err)
+		}
+
+		if user.AccountStatus != models.UserStatusPending || s.config.RequireApproval {
+			return nil
+		}
+
+		before := *user
+		user.AccountStatus = models.UserStatusActive
+		if err := s.userRepo.Update(tx, user); err != nil {
+			return fmt.Errorf("failed to activate account: %w", err)
+		}
+
+		// The patron activates their own account
+		actor.UserID = userID
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityUser, userID, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionEmailVerified,
+		IPAddress: actor.IPAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
+
+	return s.status(user, true), nil
+}
+
+// ListPending returns a page of verified accounts waiting for approval and
+// the total number waiting
+func (s *RegistrationService) ListPending(actorID int64, page, pageSize int) ([]*models.PendingRegistration, int, error) {
+	if _, err := requirePermission(s.userRepo, actorID, models.PermUsersManage); err != nil {
+		return nil, 0, err
+	}
+
+	registrations, err := s.registrationRepo.ListPending(page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending registrations: %w", err)
+	}
+
+	total, err := s.registrationRepo.CountPending()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending registrations: %w", err)
+	}
+
+	return registrations, total, nil
+}
+
+// Approve activates a pending account whose email has been verified and
+// lets the patron know
+func (s *RegistrationService) Approve(userID int64, actor models.Actor) error {
+	if _, err := requirePermission(s.userRepo, actor.UserID, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	var user *models.User
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		user, err = s.lockPending(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		verified, err := s.userRepo.IsEmailVerified(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check verification: %w", err)
+		}
+		if !verified {
+			return models.ErrEmailNotVerified
+		}
+
+		before := *user
+		user.AccountStatus = models.UserStatusActive
+		if err := s.userRepo.Update(tx, user); err != nil {
+			return fmt.Errorf("failed to activate account: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityUser, userID, &before, user)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.notificationService.NotifyAccountApproved(user); err != nil {
+		s.logger.Error("Failed to send approval notice", "user_id", userID, "error", err)
+	}
+
+	return nil
+}
+
+// Reject turns down a pending registration. The account is anonymized, so
+// the email and phone number can be used to register again.
+func (s *RegistrationService) Reject(userID int64, actor models.Actor) error {
+	if _, err := requirePermission(s.userRepo, actor.UserID, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		if _, err := s.lockPending(tx, userID); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Anonymize(tx, userID); err != nil {
+			return fmt.Errorf("failed to remove registration: %w", err)
+		}
+
+		return s.activityRepo.Create(tx, &models.ActivityLog{
+			UserID:     actorUserID(actor),
+			Action:     models.ActivityReject,
+			EntityType: models.EntityUser,
+			EntityID:   userID,
+			IPAddress:  actor.IPAddress,
+			RequestID:  actor.RequestID,
+		})
+	})
+}
+
+// Start removes expired unverified accounts immediately and then on every
+// interval until Stop is called
+func (s *RegistrationService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(time.Duration(s.config.ExpiryIntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			expired, err := s.ExpireUnverified(time.Now())
+			if err != nil {
+				s.logger.Error("Unverified registration expiry failed", "error", err)
+			}
+			if expired > 0 {
+				s.logger.Info("Unverified registrations expired", "accounts", expired)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the expiry job to finish and waits for the current run to end
+func (s *RegistrationService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// ExpireUnverified removes pending accounts whose email was not verified
+// within the verification lifetime, one batch per call. A failure on one
+// account does not stop the others; the first error is returned once all
+// are done.
+func (s *RegistrationService) ExpireUnverified(now time.Time) (int, error) {
+	cutoff := now.Add(-time.Duration(s.config.VerificationLifetimeHours) * time.Hour)
+
+	userIDs, err := s.registrationRepo.ListExpiredUnverified(now, cutoff, expiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired registrations: %w", err)
+	}
+
+	expired := 0
+	var firstErr error
+	for _, userID := range userIDs {
+		var removed bool
+		err := s.db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			removed, err = s.discardUnverified(tx, userID, models.Actor{})
+			return err
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to expire account %d: %w", userID, err)
+			}
+			continue
+		}
+		if removed {
+			expired++
+		}
+	}
+
+	return expired, firstErr
+}
+
+// discardUnverified anonymizes a pending account whose email was never
+// verified, freeing its email and phone number. It reports false, leaving
+// the account alone, once the email has been verified.
+func (s *RegistrationService) discardUnverified(tx *sql.Tx, userID int64, actor models.Actor) (bool, error) {
+	if err := s.userRepo.LockForUpdate(tx, userID); err != nil {
+		return false, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if user.AccountStatus != models.UserStatusPending {
+		return false, nil
+	}
+
+	verified, err := s.userRepo.IsEmailVerified(tx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check verification: %w", err)
+	}
+	if verified {
+		return false, nil
+	}
+
+	if err := s.userRepo.Anonymize(tx, userID); err != nil {
+		return false, err
+	}
+
+	err = s.activityRepo.Create(tx, &models.ActivityLog{
+		UserID:     actorUserID(actor),
+		Action:     models.ActivityClose,
+		EntityType: models.EntityUser,
+		EntityID:   userID,
+		IPAddress:  actor.IPAddress,
+		RequestID:  actor.RequestID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// lockPending locks an account for the transaction and makes sure it is
+// still pending
+func (s *RegistrationService) lockPending(tx *sql.Tx, userID int64) (*models.User, error) {
+	if err := s.userRepo.LockForUpdate(tx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AccountStatus != models.UserStatusPending {
+		return nil, models.ErrRegistrationNotPending
+	}
+
+	return user, nil
+}
+
+// sendVerification issues a new verification token and emails its link
+func (s *RegistrationService) sendVerification(user *models.User) error {
+	token, err := randomURLString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	lifetime := time.Duration(s.config.VerificationLifetimeHours) * time.Hour
+	err = s.registrationRepo.CreateVerificationToken(user.ID, hashVerificationToken(token), time.Now().Add(lifetime))
+	if err != nil {
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	return s.notificationService.SendEmailVerification(user, token, lifetime, s.config.RequireApproval)
+}
+
+// status describes where a registration stands
+func (s *RegistrationService) status(user *models.User, verified bool) *models.RegistrationStatus {
+	return &models.RegistrationStatus{
+		UserID:           user.ID,
+		Email:            user.Email,
+		AccountStatus:    user.AccountStatus,
+		EmailVerified:    verified,
+		AwaitingApproval: user.AccountStatus == models.UserStatusPending && s.config.RequireApproval,
+	}
+}
+
+// hashVerificationToken hashes a verification token for storage. Tokens are
+// random enough that an unsalted hash is safe.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// phoneDigits strips a phone number down to its digits for comparison
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
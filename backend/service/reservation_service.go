@@ -0,0 +1,353 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// Hold queue timing
+const (
+	holdLifetimeDays = 180 // how long a pending hold waits for a copy
+	holdPickupDays   = 7   // how long a ready hold keeps its copy set aside
+)
+
+// ReservationService manages the per-book hold queues
+type ReservationService struct {
+	db              *repository.Database
+	reservationRepo *repository.ReservationRepository
+	borrowingRepo   *repository.BorrowingRepository
+	bookRepo        *repository.BookRepository
+	userRepo        *repository.UserRepository
+
+	notificationService *NotificationService
+}
+
+// NewReservationService creates a new ReservationService instance
+func NewReservationService(
+	db *repository.Database,
+	reservationRepo *repository.ReservationRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	bookRepo *repository.BookRepository,
+	userRepo *repository.UserRepository,
+	notificationService *NotificationService,
+) *ReservationService {
+	return &ReservationService{
+		db:              db,
+		reservationRepo: reservationRepo,
+		borrowingRepo:   borrowingRepo,
+		bookRepo:        bookRepo,
+		userRepo:        userRepo,
+
+		notificationService: notificationService,
+	}
+}
+
+// PlaceHold adds a patron to the end of a book's hold queue
+func (s *ReservationService) PlaceHold(userID, bookID int64) (*models.Reservation, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AccountStatus == models.UserStatusSuspended {
+		return nil, models.ErrAccountSuspended
+	}
+	if user.AccountStatus != models.UserStatusActive {
+		return nil, models.ErrAccountNotActive
+	}
+
+	book, err := s.bookRepo.GetByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Holds are only for titles that cannot be borrowed right now
+	if book.AvailableCopies > 0 {
+		return nil, models.ErrCopiesAvailable
+	}
+
+	now := time.Now()
+	reservation := &models.Reservation{
+		UserID:          user.ID,
+		BookID:          book.ID,
+		Status:          models.ReservationStatusPending,
+		ReservationDate: now,
+		ExpiryDate:      now.AddDate(0, 0, holdLifetimeDays),
+	}
+
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.LockForUpdate(tx, user.ID); err != nil {
+			return err
+		}
+
+		exists, err := s.reservationRepo.HasOpenHold(tx, user.ID, book.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return models.ErrAlreadyOnHold
+		}
+
+		return s.reservationRepo.Create(tx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetHold(reservation.ID, user.ID)
+}
+
+// GetHold retrieves a hold with its current queue position.
+// Patrons may only see their own holds.
+func (s *ReservationService) GetHold(reservationID, actorID int64) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(nil, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerOrStaff(reservation, actorID); err != nil {
+		return nil, err
+	}
+
+	if reservation.Status == models.ReservationStatusPending {
+		position, err := s.reservationRepo.Position(reservation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get queue position: %w", err)
+		}
+		reservation.Position = position
+	}
+
+	return reservation, nil
+}
+
+// ListUserHolds retrieves a patron's open holds with their queue positions
+func (s *ReservationService) ListUserHolds(userID int64) ([]*models.Reservation, error) {
+	reservations, err := s.reservationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status != models.ReservationStatusPending {
+			continue
+		}
+		position, err := s.reservationRepo.Position(reservation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get queue position: %w", err)
+		}
+		reservation.Position = position
+	}
+
+	return reservations, nil
+}
+
+// ListQueue retrieves the open holds for a book in queue order
+func (s *ReservationService) ListQueue(bookID int64) ([]*models.Reservation, error) {
+	if _, err := s.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	reservations, err := s.reservationRepo.ListQueue(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := 0
+	for _, reservation := range reservations {
+		if reservation.Status == models.ReservationStatusPending {
+			position++
+			reservation.Position = position
+		}
+	}
+
+	return reservations, nil
+}
+
+// CancelHold removes a hold from the queue. A copy that was set aside for
+// it moves on to the next patron in line or back to the shelf.
+func (s *ReservationService) CancelHold(reservationID, actorID int64) error {
+	reservation, err := s.reservationRepo.GetByID(nil, reservationID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkOwnerOrStaff(reservation, actorID); err != nil {
+		return err
+	}
+
+	var next *models.Reservation
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		next, err = s.closeHold(tx, reservationID, models.ReservationStatusCancelled)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.AnnounceReady(next)
+	return nil
+}
+
+// CancelUserHolds cancels all of a patron's open holds in the transaction,
+// passing any copies set aside for them on. The caller announces the
+// returned holds once the transaction commits.
+func (s *ReservationService) CancelUserHolds(tx *sql.Tx, userID int64) ([]*models.Reservation, error) {
+	reservations, err := s.reservationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var readied []*models.Reservation
+	for _, reservation := range reservations {
+		next, err := s.closeHold(tx, reservation.ID, models.ReservationStatusCancelled)
+		if errors.Is(err, models.ErrReservationClosed) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			readied = append(readied, next)
+		}
+	}
+
+	return readied, nil
+}
+
+// ExpireHolds closes pending holds past their expiry date and ready holds
+// that were not picked up in time. It returns the number of holds closed.
+func (s *ReservationService) ExpireHolds(now time.Time) (int, error) {
+	expired, err := s.reservationRepo.ExpirePending(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire pending holds: %w", err)
+	}
+
+	ready, err := s.reservationRepo.ListExpiredReady(now)
+	if err != nil {
+		return int(expired), fmt.Errorf("failed to list uncollected holds: %w", err)
+	}
+
+	closed := int(expired)
+	for _, reservation := range ready {
+		var next *models.Reservation
+		err := s.db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			next, err = s.closeHold(tx, reservation.ID, models.ReservationStatusExpired)
+			return err
+		})
+		if err != nil && !errors.Is(err, models.ErrReservationClosed) {
+			return closed, fmt.Errorf("failed to expire hold %d: %w", reservation.ID, err)
+		}
+		if err == nil {
+			closed++
+			s.AnnounceReady(next)
+		}
+	}
+
+	return closed, nil
+}
+
+// SetAsideForNextHold gives a copy that just came back to the first patron
+// waiting for the book. The copy must already be back on the shelf. It
+// returns the hold that is now ready for pickup, or nil when nobody waits.
+func (s *ReservationService) SetAsideForNextHold(tx *sql.Tx, bookID, bookCopyID int64) (*models.Reservation, error) {
+	next, err := s.reservationRepo.NextPending(tx, bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrReservationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	pickupExpiresAt := now.AddDate(0, 0, holdPickupDays)
+	if err := s.reservationRepo.MarkReady(tx, next.ID, bookCopyID, now, pickupExpiresAt); err != nil {
+		return nil, err
+	}
+
+	next.Status = models.ReservationStatusReady
+	next.BookCopyID = &bookCopyID
+	next.ReadyDate = &now
+	next.PickupExpiresAt = &pickupExpiresAt
+
+	if err := s.borrowingRepo.UpdateBookCopyStatus(tx, bookCopyID, models.BookCopyStatusReserved); err != nil {
+		return nil, err
+	}
+
+	if err := s.borrowingRepo.SyncBookCounts(tx, bookID); err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// AnnounceReady tells the patron of a hold that was just set aside that it
+// can be picked up. Call it once the transaction that set it aside commits.
+func (s *ReservationService) AnnounceReady(reservation *models.Reservation) {
+	if reservation == nil {
+		return
+	}
+
+	s.notificationService.NotifyHoldReady(reservation)
+}
+
+// ClaimHoldForCheckout fulfils the ready hold a reserved copy was set aside
+// for. It fails unless the copy is being checked out by that hold's patron.
+func (s *ReservationService) ClaimHoldForCheckout(tx *sql.Tx, bookCopyID, userID int64) error {
+	hold, err := s.reservationRepo.GetReadyForCopy(tx, bookCopyID)
+	if err != nil {
+		if errors.Is(err, models.ErrReservationNotFound) {
+			return models.ErrBookCopyNotAvailable
+		}
+		return err
+	}
+
+	if hold.UserID != userID {
+		return models.ErrBookCopyNotAvailable
+	}
+
+	return s.reservationRepo.UpdateStatus(tx, hold.ID, models.ReservationStatusFulfilled)
+}
+
+// closeHold ends an open hold and releases any copy set aside for it. It
+// returns the hold the copy was passed on to, if any.
+func (s *ReservationService) closeHold(tx *sql.Tx, reservationID int64, status string) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(tx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reservation.IsOpen() {
+		return nil, models.ErrReservationClosed
+	}
+
+	if err := s.reservationRepo.UpdateStatus(tx, reservation.ID, status); err != nil {
+		return nil, err
+	}
+
+	if reservation.Status != models.ReservationStatusReady || reservation.BookCopyID == nil {
+		return nil, nil
+	}
+
+	// Put the copy back on the shelf, then offer it to the next patron in line
+	copyID := *reservation.BookCopyID
+	if err := s.borrowingRepo.UpdateBookCopyStatus(tx, copyID, models.BookCopyStatusAvailable); err != nil {
+		return nil, err
+	}
+	if err := s.borrowingRepo.SyncBookCounts(tx, reservation.BookID); err != nil {
+		return nil, err
+	}
+
+	return s.SetAsideForNextHold(tx, reservation.BookID, copyID)
+}
+
+// checkOwnerOrStaff makes sure the acting user may see or change a hold
+func (s *ReservationService) checkOwnerOrStaff(reservation *models.Reservation, actorID int64) error {
+	return requireSelfOrPermission(s.userRepo, reservation.UserID, actorID, models.PermHoldsManage)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// authLogWriter stores auth log entries. The auth log repository implements it.
+type authLogWriter interface {
+	Create(log *models.AuthLog) error
+	CreateWithSeverity(log *models.AuthLog) error
+}
+
+// SessionService lists and revokes the devices a user is signed in on
+type SessionService struct {
+	sessionRepo         *repository.SessionRepository
+	userRepo            *repository.UserRepository
+	authLogRepo         authLogWriter
+	notificationService *NotificationService
+
+	config config.SessionConfig
+}
+
+// NewSessionService creates a new SessionService instance
+func NewSessionService(
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	authLogRepo *repository.AuthLogRepository,
+	notificationService *NotificationService,
+	cfg config.SessionConfig,
+) *SessionService {
+	return &SessionService{
+		sessionRepo:         sessionRepo,
+		userRepo:            userRepo,
+		authLogRepo:         authLogRepo,
+		notificationService: notificationService,
+
+		config: cfg,
+	}
+}
+
+// ListSessions returns a user's active sessions. Users may list their own;
+// admins may list anyone's.
+func (s *SessionService) ListSessions(userID, actorID int64) ([]*models.Session, error) {
+	if err := requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActive(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a user out of one session
+func (s *SessionService) RevokeSession(userID, sessionID, actorID int64, ipAddress, userAgent string) error {
+	if err := requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.Revoke(userID, sessionID, models.SessionRevokedByUser); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Session %d revoked", sessionID)
+	if actorID != userID {
+		details = fmt.Sprintf("Session %d revoked by user %d", sessionID, actorID)
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    userID,
+		Action:    models.AuthActionLogout,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+		Details:   details,
+	})
+
+	return nil
+}
+
+// RevokeForTokenReuse ends the token family of a session after one of its
+// rotated refresh tokens came back, which means the token was copied. Users
+// with no session on record only get the log entry.
+func (s *SessionService) RevokeForTokenReuse(userID, sessionID int64, ipAddress, userAgent string) error {
+	s.authLogRepo.CreateWithSeverity(&models.AuthLog{
+		UserID:    userID,
+		Action:    models.AuthActionRefreshTokenReuse,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusFailure,
+		Severity:  models.AuthSeverityHigh,
+		Details:   fmt.Sprintf("Rotated refresh token of session %d presented again", sessionID),
+	})
+
+	if sessionID == 0 {
+		return nil
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	err = s.sessionRepo.Revoke(userID, sessionID, models.SessionRevokedTokenReuse)
+	if errors.Is(err, models.ErrSessionNotFound) {
+		// Already revoked by an earlier reuse
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if !s.config.NotifyOnTokenReuse {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.notificationService.NotifySessionRevoked(user, session.Device, ipAddress)
+}
+
+// describeDevice turns a user agent into a short label such as
+// "Firefox on Windows" for the session list
+func describeDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(ua, "android"):
+		platform = "Android"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		platform = "iOS"
+	case strings.Contains(ua, "windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "mac os"):
+		platform = "macOS"
+	case strings.Contains(ua, "linux"):
+		platform = "Linux"
+	}
+
+	if platform == "" {
+		return browser
+	}
+	return browser + " on " + platform
+}
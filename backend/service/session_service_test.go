@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"library-management-system/internal/models"
+)
+
+// fakeAuthLog keeps the auth log entries written through each method
+type fakeAuthLog struct {
+	plain    []*models.AuthLog
+	severity []*models.AuthLog
+}
+
+func (f *fakeAuthLog) Create(log *models.AuthLog) error {
+	f.plain = append(f.plain, log)
+	return nil
+}
+
+func (f *fakeAuthLog) CreateWithSeverity(log *models.AuthLog) error {
+	f.severity = append(f.severity, log)
+	return nil
+}
+
+func TestRevokeForTokenReuseLogsHighSeverity(t *testing.T) {
+	authLog := &fakeAuthLog{}
+	service := &SessionService{authLogRepo: authLog}
+
+	// Without a session on record only the log entry is written
+	if err := service.RevokeForTokenReuse(7, 0, "203.0.113.9", "curl/8.0"); err != nil {
+		t.Fatalf("RevokeForTokenReuse() error = %v", err)
+	}
+
+	if len(authLog.plain) != 0 {
+		t.Errorf("wrote %d entries without a severity, want 0", len(authLog.plain))
+	}
+	if len(authLog.severity) != 1 {
+		t.Fatalf("wrote %d entries with a severity, want 1", len(authLog.severity))
+	}
+
+	entry := authLog.severity[0]
+	if entry.Severity != models.AuthSeverityHigh {
+		t.Errorf("Severity = %q, want %q", entry.Severity, models.AuthSeverityHigh)
+	}
+	if entry.Action != models.AuthActionRefreshTokenReuse || entry.UserID != 7 || entry.Status != models.StatusFailure {
+		t.Errorf("entry = %+v, want a failed refresh_token_reuse for user 7", entry)
+	}
+}
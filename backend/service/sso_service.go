@@ -0,0 +1,352 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ssoUserStore is the part of the user repository sign-in needs
+type ssoUserStore interface {
+	GetByID(id int64) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	Create(tx *sql.Tx, user *models.User) error
+	Update(tx *sql.Tx, user *models.User) error
+	IsEmailVerified(tx *sql.Tx, userID int64) (bool, error)
+	MarkEmailVerified(tx *sql.Tx, userID int64) error
+}
+
+// ssoIdentityStore holds linked provider identities and pending sign-ins.
+// Together with ssoUserStore it lets the login flow run against a mock
+// provider without a database.
+type ssoIdentityStore interface {
+	GetBySubject(issuer, subject string) (*models.UserIdentity, error)
+	Create(tx *sql.Tx, identity *models.UserIdentity) error
+	RecordLogin(id int64, email string) error
+	CreateState(state *models.SSOLoginState) error
+	ConsumeState(state string, now time.Time) (*models.SSOLoginState, error)
+	DeleteExpiredStates(now time.Time) (int64, error)
+}
+
+// ssoTokenIssuer signs a resolved account in. The auth service implements it.
+type ssoTokenIssuer interface {
+	LoginExternal(user *models.User, method, ipAddress, userAgent string) (*models.LoginResult, error)
+}
+
+// ssoTransactor runs work in a database transaction
+type ssoTransactor interface {
+	Transaction(fn func(*sql.Tx) error) error
+}
+
+// SSOService signs users in through the campus OpenID Connect provider,
+// creating or linking local accounts as needed
+type SSOService struct {
+	db           ssoTransactor
+	userRepo     ssoUserStore
+	identityRepo ssoIdentityStore
+	activityRepo activityRecorder
+	authService  ssoTokenIssuer
+	logger       *logger.Logger
+
+	provider *oidcProvider
+	config   config.OIDCConfig
+}
+
+// NewSSOService creates a new SSOService instance
+func NewSSOService(
+	db *repository.Database,
+	userRepo *repository.UserRepository,
+	identityRepo *repository.IdentityRepository,
+	activityRepo *repository.ActivityLogRepository,
+	authService *AuthService,
+	httpClient *http.Client,
+	logger *logger.Logger,
+	cfg config.OIDCConfig,
+) *SSOService {
+	return &SSOService{
+		db:           db,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		activityRepo: activityRepo,
+		authService:  authService,
+		logger:       logger,
+
+		provider: newOIDCProvider(cfg.IssuerURL, cfg.ClientID, cfg.ClientSecret, cfg.RedirectURL, httpClient),
+		config:   cfg,
+	}
+}
+
+// BeginLogin starts an authorization code flow with PKCE and returns the
+// provider address to send the browser to
+func (s *SSOService) BeginLogin() (string, error) {
+	if !s.config.Enabled {
+		return "", models.ErrSSODisabled
+	}
+
+	state, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomURLString(64)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	authURL, err := s.provider.authorizationURL(state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]), s.config.Scopes)
+	if err != nil {
+		s.logger.Error("Identity provider unavailable", "error", err)
+		return "", models.ErrSSOLoginFailed
+	}
+
+	err = s.identityRepo.CreateState(&models.SSOLoginState{
+		State:        state,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(time.Duration(s.config.StateLifetimeMinutes) * time.Minute),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save sign-in request: %w", err)
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin exchanges the code the provider sent back and signs the user
+// in, creating their account on first sign-in. The actor is the anonymous
+// request, used for the audit trail of account changes.
+func (s *SSOService) CompleteLogin(state, code string, actor models.Actor, userAgent string) (*models.LoginResult, error) {
+	if !s.config.Enabled {
+		return nil, models.ErrSSODisabled
+	}
+
+	now := time.Now()
+	if _, err := s.identityRepo.DeleteExpiredStates(now); err != nil {
+		s.logger.Error("Failed to delete expired sign-in requests", "error", err)
+	}
+
+	pending, err := s.identityRepo.ConsumeState(state, now)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.provider.exchange(code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		s.logger.Error("OIDC code exchange failed", "error", err)
+		return nil, models.ErrSSOLoginFailed
+	}
+
+	user, err := s.resolveUser(claims, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.LoginExternal(user, "Single sign-on", actor.IPAddress, userAgent)
+}
+
+// resolveUser finds the account for verified ID token claims. Known subjects
+// use their linked account, new subjects are linked to an account with the
+// same email once both the provider and the library have verified it, and
+// otherwise a member account is created.
+func (s *SSOService) resolveUser(claims jwt.MapClaims, actor models.Actor) (*models.User, error) {
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, models.ErrSSOLoginFailed
+	}
+
+	email := strings.ToLower(strings.TrimSpace(stringClaim(claims, "email")))
+	role := s.mapRole(claims)
+
+	identity, err := s.identityRepo.GetBySubject(s.config.IssuerURL, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	if identity != nil {
+		user, err := s.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.identityRepo.RecordLogin(identity.ID, email); err != nil {
+			return nil, fmt.Errorf("failed to record sign-in: %w", err)
+		}
+		return s.syncRole(user, role, actor)
+	}
+
+	// Only a provider-verified address may claim an existing account
+	if email == "" {
+		return nil, models.ErrSSOLoginFailed
+	}
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		return nil, models.ErrSSOEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	if user != nil {
+		// An address nobody verified on our side may belong to someone who
+		// registered it first to wait for its owner's sign-in
+		emailVerified, err := s.userRepo.IsEmailVerified(nil, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email verification: %w", err)
+		}
+		if !emailVerified {
+			return nil, models.ErrEmailNotVerified
+		}
+
+		err = s.identityRepo.Create(nil, &models.UserIdentity{
+			UserID: user.ID, Issuer: s.config.IssuerURL, Subject: subject, Email: email,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to link identity: %w", err)
+		}
+		return s.syncRole(user, role, actor)
+	}
+
+	return s.createUser(claims, subject, email, role, actor)
+}
+
+// createUser creates an account just in time for a first sign-in. It gets a
+// random password, so it can only sign in through the provider until the
+// user resets it.
+func (s *SSOService) createUser(claims jwt.MapClaims, subject, email string, role models.UserRole, actor models.Actor) (*models.User, error) {
+	password, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	fullName := stringClaim(claims, "name")
+	if fullName == "" {
+		fullName = strings.TrimSpace(stringClaim(claims, "given_name") + " " + stringClaim(claims, "family_name"))
+	}
+	if fullName == "" {
+		fullName = email
+	}
+
+	user := &models.User{
+		Email:         email,
+		PasswordHash:  string(hash),
+		FullName:      fullName,
+		Role:          role,
+		AccountStatus: models.UserStatusActive,
+	}
+
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.Create(tx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		// The provider has verified the address
+		if err := s.userRepo.MarkEmailVerified(tx, user.ID); err != nil {
+			return fmt.Errorf("failed to verify email: %w", err)
+		}
+		if err := recordChange(s.activityRepo, tx, actor, models.ActivityCreate, models.EntityUser, user.ID, nil, user); err != nil {
+			return err
+		}
+
+		err := s.identityRepo.Create(tx, &models.UserIdentity{
+			UserID: user.ID, Issuer: s.config.IssuerURL, Subject: subject, Email: email,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Created account from single sign-on", "user_id", user.ID, "role", user.Role)
+	return user, nil
+}
+
+// syncRole applies the role from the claims when role syncing is enabled
+func (s *SSOService) syncRole(user *models.User, role models.UserRole, actor models.Actor) (*models.User, error) {
+	// Super admins are managed locally only
+	if !s.config.SyncRoles || user.Role == role || user.Role == models.UserRoleSuperAdmin {
+		return user, nil
+	}
+
+	updated := *user
+	updated.Role = role
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.Update(tx, &updated); err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+
+		return recordChange(s.activityRepo, tx, actor, models.ActivityUpdate, models.EntityUser, user.ID, user, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// mapRole picks the most privileged role whose groups appear in the claims
+func (s *SSOService) mapRole(claims jwt.MapClaims) models.UserRole {
+	groups := make(map[string]bool)
+	switch value := claims[s.config.RoleClaim].(type) {
+	case string:
+		groups[value] = true
+	case []interface{}:
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups[name] = true
+			}
+		}
+	}
+
+	for _, group := range s.config.AdminGroups {
+		if groups[group] {
+			return models.UserRoleAdmin
+		}
+	}
+	for _, group := range s.config.StaffGroups {
+		if groups[group] {
+			return models.UserRoleStaff
+		}
+	}
+
+	return models.UserRoleMember
+}
+
+// stringClaim reads a string claim, returning "" when it is missing
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// randomURLString returns n random bytes encoded for use in URLs
+func randomURLString(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
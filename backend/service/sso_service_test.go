@@ -0,0 +1,401 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeSSOUsers keeps accounts and their email verification in memory
+type fakeSSOUsers struct {
+	users    map[int64]*models.User
+	verified map[int64]bool
+	nextID   int64
+}
+
+func newFakeSSOUsers() *fakeSSOUsers {
+	return &fakeSSOUsers{users: make(map[int64]*models.User), verified: make(map[int64]bool), nextID: 1}
+}
+
+// add stores an existing account
+func (f *fakeSSOUsers) add(user *models.User, emailVerified bool) {
+	user.ID = f.nextID
+	f.nextID++
+	f.users[user.ID] = user
+	f.verified[user.ID] = emailVerified
+}
+
+func (f *fakeSSOUsers) GetByID(id int64) (*models.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeSSOUsers) GetByEmail(email string) (*models.User, error) {
+	for _, user := range f.users {
+		if strings.EqualFold(user.Email, email) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (f *fakeSSOUsers) Create(tx *sql.Tx, user *models.User) error {
+	copied := *user
+	f.add(&copied, false)
+	user.ID = copied.ID
+	return nil
+}
+
+func (f *fakeSSOUsers) Update(tx *sql.Tx, user *models.User) error {
+	copied := *user
+	f.users[user.ID] = &copied
+	return nil
+}
+
+func (f *fakeSSOUsers) IsEmailVerified(tx *sql.Tx, userID int64) (bool, error) {
+	return f.verified[userID], nil
+}
+
+func (f *fakeSSOUsers) MarkEmailVerified(tx *sql.Tx, userID int64) error {
+	f.verified[userID] = true
+	return nil
+}
+
+// fakeIdentities keeps linked identities and pending sign-ins in memory
+type fakeIdentities struct {
+	identities []*models.UserIdentity
+	states     map[string]*models.SSOLoginState
+	logins     int
+}
+
+func newFakeIdentities() *fakeIdentities {
+	return &fakeIdentities{states: make(map[string]*models.SSOLoginState)}
+}
+
+func (f *fakeIdentities) GetBySubject(issuer, subject string) (*models.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeIdentities) Create(tx *sql.Tx, identity *models.UserIdentity) error {
+	identity.ID = int64(len(f.identities) + 1)
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+func (f *fakeIdentities) RecordLogin(id int64, email string) error {
+	f.logins++
+	return nil
+}
+
+func (f *fakeIdentities) CreateState(state *models.SSOLoginState) error {
+	f.states[state.State] = state
+	return nil
+}
+
+func (f *fakeIdentities) ConsumeState(state string, now time.Time) (*models.SSOLoginState, error) {
+	pending, ok := f.states[state]
+	delete(f.states, state)
+	if !ok || !pending.ExpiresAt.After(now) {
+		return nil, models.ErrInvalidSSOState
+	}
+	return pending, nil
+}
+
+func (f *fakeIdentities) DeleteExpiredStates(now time.Time) (int64, error) {
+	var deleted int64
+	for state, pending := range f.states {
+		if !pending.ExpiresAt.After(now) {
+			delete(f.states, state)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakeActivity counts audit trail entries
+type fakeActivity struct {
+	entries []*models.ActivityLog
+}
+
+func (f *fakeActivity) Create(tx *sql.Tx, log *models.ActivityLog) error {
+	f.entries = append(f.entries, log)
+	return nil
+}
+
+// fakeTokenIssuer stands in for the auth service and records who it signed in
+type fakeTokenIssuer struct {
+	logins []*models.User
+}
+
+func (f *fakeTokenIssuer) LoginExternal(user *models.User, method, ipAddress, userAgent string) (*models.LoginResult, error) {
+	f.logins = append(f.logins, user)
+	return &models.LoginResult{User: user, Tokens: &models.TokenPair{AccessToken: "access-token"}}, nil
+}
+
+// fakeTransactor runs work without a database
+type fakeTransactor struct{}
+
+func (fakeTransactor) Transaction(fn func(*sql.Tx) error) error {
+	return fn(nil)
+}
+
+// ssoFixture wires an SSOService to a mock provider and in-memory stores
+type ssoFixture struct {
+	idp        *mockIdP
+	users      *fakeSSOUsers
+	identities *fakeIdentities
+	activity   *fakeActivity
+	issuer     *fakeTokenIssuer
+	service    *SSOService
+}
+
+func newSSOFixture(t *testing.T) *ssoFixture {
+	t.Helper()
+
+	idp := newMockIdP(t)
+	cfg := config.OIDCConfig{
+		Enabled:              true,
+		IssuerURL:            idp.server.URL,
+		ClientID:             mockClientID,
+		ClientSecret:         mockClientSecret,
+		RedirectURL:          mockRedirectURL,
+		Scopes:               []string{"openid", "email", "profile"},
+		RoleClaim:            "groups",
+		StaffGroups:          []string{"library-staff"},
+		AdminGroups:          []string{"library-admins"},
+		StateLifetimeMinutes: 10,
+	}
+
+	f := &ssoFixture{
+		idp:        idp,
+		users:      newFakeSSOUsers(),
+		identities: newFakeIdentities(),
+		activity:   &fakeActivity{},
+		issuer:     &fakeTokenIssuer{},
+	}
+	f.service = &SSOService{
+		db:           fakeTransactor{},
+		userRepo:     f.users,
+		identityRepo: f.identities,
+		activityRepo: f.activity,
+		authService:  f.issuer,
+		logger:       logger.NewLogger(),
+		provider:     newOIDCProvider(cfg.IssuerURL, cfg.ClientID, cfg.ClientSecret, cfg.RedirectURL, idp.server.Client()),
+		config:       cfg,
+	}
+
+	return f
+}
+
+// authorize starts a sign-in and follows it through the mock provider,
+// returning the state and code the browser brings back
+func (f *ssoFixture) authorize(t *testing.T, claims jwt.MapClaims) (state, code string) {
+	t.Helper()
+
+	authURL, err := f.service.BeginLogin()
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	if parsed.Query().Get("code_challenge") == "" {
+		t.Fatal("authorization URL has no PKCE challenge")
+	}
+
+	f.idp.setClaims(claims)
+	code, state = f.idp.signIn(t, authURL)
+	return state, code
+}
+
+// signIn runs a whole sign-in through CompleteLogin
+func (f *ssoFixture) signIn(t *testing.T, claims jwt.MapClaims) (*models.LoginResult, error) {
+	t.Helper()
+
+	state, code := f.authorize(t, claims)
+	return f.service.CompleteLogin(state, code, models.Actor{IPAddress: "127.0.0.1"}, "test-browser")
+}
+
+func TestSSOCreatesAccountOnFirstSignIn(t *testing.T) {
+	f := newSSOFixture(t)
+
+	result, err := f.signIn(t, jwt.MapClaims{
+		"sub":            "campus-42",
+		"email":          "Ada@Example.edu",
+		"email_verified": true,
+		"given_name":     "Ada",
+		"family_name":    "Lovelace",
+		"groups":         []string{"students", "library-staff"},
+	})
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+
+	user := result.User
+	if result.Tokens == nil || len(f.issuer.logins) != 1 || f.issuer.logins[0].ID != user.ID {
+		t.Errorf("tokens were not issued for the new account")
+	}
+	if user.Email != "ada@example.edu" || user.FullName != "Ada Lovelace" {
+		t.Errorf("created %q <%s>, want %q <%s>", user.FullName, user.Email, "Ada Lovelace", "ada@example.edu")
+	}
+	if user.Role != models.UserRoleStaff {
+		t.Errorf("Role = %q, want %q from the groups claim", user.Role, models.UserRoleStaff)
+	}
+	if !f.users.verified[user.ID] {
+		t.Error("email of a provider-created account is not marked verified")
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].UserID != user.ID {
+		t.Errorf("identities = %v, want one linked to the new account", f.identities.identities)
+	}
+	if len(f.activity.entries) != 1 {
+		t.Errorf("recorded %d audit entries, want 1", len(f.activity.entries))
+	}
+
+	// The next sign-in uses the linked identity
+	again, err := f.signIn(t, jwt.MapClaims{"sub": "campus-42", "email": "ada@example.edu", "email_verified": true})
+	if err != nil {
+		t.Fatalf("second CompleteLogin() error = %v", err)
+	}
+	if again.User.ID != user.ID || len(f.users.users) != 1 || f.identities.logins != 1 {
+		t.Errorf("second sign-in resolved to %d with %d accounts and %d recorded logins, want the same account", again.User.ID, len(f.users.users), f.identities.logins)
+	}
+}
+
+func TestSSOLinksExistingAccountByEmail(t *testing.T) {
+	f := newSSOFixture(t)
+	existing := &models.User{Email: "grace@example.edu", FullName: "Grace Hopper", Role: models.UserRoleMember}
+	f.users.add(existing, true)
+
+	result, err := f.signIn(t, jwt.MapClaims{"sub": "campus-7", "email": "grace@example.edu", "email_verified": true})
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+
+	if user := result.User; user.ID != existing.ID || len(f.users.users) != 1 {
+		t.Errorf("signed in as %d with %d accounts, want the existing account %d", result.User.ID, len(f.users.users), existing.ID)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].Subject != "campus-7" {
+		t.Errorf("identities = %v, want the subject linked", f.identities.identities)
+	}
+}
+
+func TestSSORefusesToLinkUnverifiedEmails(t *testing.T) {
+	tests := []struct {
+		name          string
+		localVerified bool
+		claims        jwt.MapClaims
+		want          error
+	}{
+		{
+			name:          "provider has not verified the email",
+			localVerified: true,
+			claims:        jwt.MapClaims{"sub": "campus-7", "email": "grace@example.edu", "email_verified": false},
+			want:          models.ErrSSOEmailNotVerified,
+		},
+		{
+			name:          "local account has not verified the email",
+			localVerified: false,
+			claims:        jwt.MapClaims{"sub": "campus-7", "email": "grace@example.edu", "email_verified": true},
+			want:          models.ErrEmailNotVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSSOFixture(t)
+			f.users.add(&models.User{Email: "grace@example.edu", Role: models.UserRoleMember}, tt.localVerified)
+
+			_, err := f.signIn(t, tt.claims)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CompleteLogin() error = %v, want %v", err, tt.want)
+			}
+			if len(f.identities.identities) != 0 || len(f.issuer.logins) != 0 {
+				t.Errorf("linked %d identities and signed in %d times, want none", len(f.identities.identities), len(f.issuer.logins))
+			}
+		})
+	}
+}
+
+func TestSSOCompleteLoginRejectsBadState(t *testing.T) {
+	claims := jwt.MapClaims{"sub": "campus-42", "email": "ada@example.edu", "email_verified": true}
+	actor := models.Actor{IPAddress: "127.0.0.1"}
+
+	t.Run("unknown state", func(t *testing.T) {
+		f := newSSOFixture(t)
+		_, code := f.authorize(t, claims)
+
+		_, err := f.service.CompleteLogin("not-a-state", code, actor, "test-browser")
+		if !errors.Is(err, models.ErrInvalidSSOState) {
+			t.Fatalf("CompleteLogin() error = %v, want %v", err, models.ErrInvalidSSOState)
+		}
+	})
+
+	t.Run("replayed state", func(t *testing.T) {
+		f := newSSOFixture(t)
+		state, code := f.authorize(t, claims)
+
+		if _, err := f.service.CompleteLogin(state, code, actor, "test-browser"); err != nil {
+			t.Fatalf("first CompleteLogin() error = %v", err)
+		}
+		_, err := f.service.CompleteLogin(state, code, actor, "test-browser")
+		if !errors.Is(err, models.ErrInvalidSSOState) {
+			t.Fatalf("replayed CompleteLogin() error = %v, want %v", err, models.ErrInvalidSSOState)
+		}
+		if len(f.issuer.logins) != 1 {
+			t.Errorf("signed in %d times, want once", len(f.issuer.logins))
+		}
+	})
+
+	t.Run("expired state", func(t *testing.T) {
+		f := newSSOFixture(t)
+		state, code := f.authorize(t, claims)
+		f.identities.states[state].ExpiresAt = time.Now().Add(-time.Second)
+
+		_, err := f.service.CompleteLogin(state, code, actor, "test-browser")
+		if !errors.Is(err, models.ErrInvalidSSOState) {
+			t.Fatalf("CompleteLogin() error = %v, want %v", err, models.ErrInvalidSSOState)
+		}
+		if len(f.issuer.logins) != 0 {
+			t.Errorf("signed in %d times with an expired state, want 0", len(f.issuer.logins))
+		}
+	})
+}
+
+func TestSSOCompleteLoginHidesProviderErrors(t *testing.T) {
+	f := newSSOFixture(t)
+	f.idp.nonce = "someone-elses-nonce"
+
+	_, err := f.signIn(t, jwt.MapClaims{"sub": "campus-42", "email": "ada@example.edu", "email_verified": true})
+	if !errors.Is(err, models.ErrSSOLoginFailed) {
+		t.Fatalf("CompleteLogin() error = %v, want %v", err, models.ErrSSOLoginFailed)
+	}
+	if len(f.users.users) != 0 || len(f.issuer.logins) != 0 {
+		t.Errorf("created %d accounts and signed in %d times, want none", len(f.users.users), len(f.issuer.logins))
+	}
+}
+
+func TestSSOCompleteLoginRequiresEnabled(t *testing.T) {
+	f := newSSOFixture(t)
+	f.service.config.Enabled = false
+
+	if _, err := f.service.CompleteLogin("state", "code", models.Actor{}, "test-browser"); !errors.Is(err, models.ErrSSODisabled) {
+		t.Fatalf("CompleteLogin() error = %v, want %v", err, models.ErrSSODisabled)
+	}
+}
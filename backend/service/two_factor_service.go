@@ -0,0 +1,284 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// recoveryCodeEncoding spells recovery codes in lowercase base32
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// TwoFactorService enrolls users in TOTP two-factor authentication and
+// checks their codes
+type TwoFactorService struct {
+	userRepo            *repository.UserRepository
+	twoFactorRepo       *repository.TwoFactorRepository
+	authLogRepo         *repository.AuthLogRepository
+	notificationService *NotificationService
+
+	secrets *secretBox
+	config  config.TwoFactorConfig
+}
+
+// NewTwoFactorService creates a new TwoFactorService instance. It fails when
+// no key for encrypting secrets is configured.
+func NewTwoFactorService(
+	userRepo *repository.UserRepository,
+	twoFactorRepo *repository.TwoFactorRepository,
+	authLogRepo *repository.AuthLogRepository,
+	notificationService *NotificationService,
+	cfg config.TwoFactorConfig,
+) (*TwoFactorService, error) {
+	secrets, err := newSecretBox(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorService{
+		userRepo:            userRepo,
+		twoFactorRepo:       twoFactorRepo,
+		authLogRepo:         authLogRepo,
+		notificationService: notificationService,
+
+		secrets: secrets,
+		config:  cfg,
+	}, nil
+}
+
+// BeginEnrollment creates a new TOTP secret for a user. It stays inactive
+// until ConfirmEnrollment sees a valid code from the authenticator app.
+func (s *TwoFactorService) BeginEnrollment(userID int64) (*models.TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	sealed, err := s.secrets.seal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	if err := s.userRepo.SetTwoFactorSecret(userID, sealed, false); err != nil {
+		return nil, fmt.Errorf("failed to save secret: %w", err)
+	}
+
+	uri := totpURI(s.config.Issuer, user.Email, secret)
+	return &models.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRPayload:  uri,
+	}, nil
+}
+
+// ConfirmEnrollment turns on two-factor authentication once the user proves
+// their authenticator app works. It returns the user's recovery codes, which
+// are only ever shown this once.
+func (s *TwoFactorService) ConfirmEnrollment(userID int64, code, ipAddress, userAgent string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+
+	sealed, secret, err := s.loadSecret(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.ResetSteps(nil, userID); err != nil {
+		return nil, fmt.Errorf("failed to reset code history: %w", err)
+	}
+	if err := s.checkTOTP(userID, secret, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetTwoFactorSecret(userID, sealed, true); err != nil {
+		return nil, fmt.Errorf("failed to enable 2FA: %w", err)
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionEnable2FA,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
+
+	if err := s.notificationService.NotifyTwoFactorChanged(user, true); err != nil {
+		return codes, fmt.Errorf("failed to send 2FA notice: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication after checking a current code
+// or recovery code, and discards the secret and recovery codes
+func (s *TwoFactorService) Disable(userID int64, code, ipAddress, userAgent string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return models.ErrTwoFactorNotEnabled
+	}
+
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ClearTwoFactorSecret(userID); err != nil {
+		return fmt.Errorf("failed to disable 2FA: %w", err)
+	}
+	if err := s.twoFactorRepo.DeleteRecoveryCodes(nil, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	s.authLogRepo.Create(&models.AuthLog{
+		UserID:    user.ID,
+		Action:    models.AuthActionDisable2FA,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Status:    models.StatusSuccess,
+	})
+
+	return s.notificationService.NotifyTwoFactorChanged(user, false)
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes after checking a
+// current code
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID int64, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, models.ErrTwoFactorNotEnabled
+	}
+
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(userID)
+}
+
+// Verify checks a code from the user's authenticator app or one of their
+// recovery codes. Each code is accepted only once.
+func (s *TwoFactorService) Verify(userID int64, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits {
+		_, secret, err := s.loadSecret(userID)
+		if err != nil {
+			return err
+		}
+		return s.checkTOTP(userID, secret, code)
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(userID, hashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if !used {
+		return models.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// mfaTokenLifetime is how long a pending login waits for a code
+func (s *TwoFactorService) mfaTokenLifetime() time.Duration {
+	return time.Duration(s.config.MFATokenLifetimeMinutes) * time.Minute
+}
+
+// checkTOTP verifies a TOTP code and claims its time step against replays
+func (s *TwoFactorService) checkTOTP(userID int64, secret, code string) error {
+	step, ok := verifyTOTP(secret, code, time.Now())
+	if !ok {
+		return models.ErrInvalidTwoFactorCode
+	}
+
+	claimed, err := s.twoFactorRepo.ClaimStep(userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record code: %w", err)
+	}
+	if !claimed {
+		return models.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// loadSecret returns a user's encrypted and decrypted TOTP secret
+func (s *TwoFactorService) loadSecret(userID int64) (string, string, error) {
+	sealed, err := s.userRepo.GetTwoFactorSecret(userID)
+	if err != nil {
+		if errors.Is(err, models.ErrTwoFactorNotEnabled) {
+			return "", "", models.ErrTwoFactorNotEnrolled
+		}
+		return "", "", fmt.Errorf("failed to load secret: %w", err)
+	}
+
+	secret, err := s.secrets.open(sealed)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sealed, secret, nil
+}
+
+// issueRecoveryCodes replaces a user's recovery codes with new random ones
+func (s *TwoFactorService) issueRecoveryCodes(userID int64) ([]string, error) {
+	codes := make([]string, s.config.RecoveryCodeCount)
+	hashes := make([]string, s.config.RecoveryCodeCount)
+
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		encoded := recoveryCodeEncoding.EncodeToString(raw)
+		codes[i] = encoded[:8] + "-" + encoded[8:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(nil, userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// hashRecoveryCode normalises a recovery code as typed and hashes it.
+// Codes are random enough that an unsalted hash is safe.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
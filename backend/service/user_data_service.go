@@ -0,0 +1,197 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-system/internal/config"
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+)
+
+// exportPageSize is the page size used to read a patron's full history
+const exportPageSize = 100
+
+// UserDataService closes patron accounts and exports the data held about
+// them
+type UserDataService struct {
+	db                 *repository.Database
+	userRepo           *repository.UserRepository
+	borrowingRepo      *repository.BorrowingRepository
+	fineRepo           *repository.FineRepository
+	ledgerRepo         *repository.LedgerRepository
+	reservationRepo    *repository.ReservationRepository
+	authLogRepo        *repository.AuthLogRepository
+	activityRepo       *repository.ActivityLogRepository
+	reservationService *ReservationService
+	config             config.AuditConfig
+}
+
+// NewUserDataService creates a new UserDataService instance
+func NewUserDataService(
+	db *repository.Database,
+	userRepo *repository.UserRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	fineRepo *repository.FineRepository,
+	ledgerRepo *repository.LedgerRepository,
+	reservationRepo *repository.ReservationRepository,
+	authLogRepo *repository.AuthLogRepository,
+	activityRepo *repository.ActivityLogRepository,
+	reservationService *ReservationService,
+	cfg config.AuditConfig,
+) *UserDataService {
+	return &UserDataService{
+		db:                 db,
+		userRepo:           userRepo,
+		borrowingRepo:      borrowingRepo,
+		fineRepo:           fineRepo,
+		ledgerRepo:         ledgerRepo,
+		reservationRepo:    reservationRepo,
+		authLogRepo:        authLogRepo,
+		activityRepo:       activityRepo,
+		reservationService: reservationService,
+		config:             cfg,
+	}
+}
+
+// CloseAccount closes a patron's account. It is refused while loans are out
+// or a balance is owed. The account is anonymized rather than deleted, so
+// its loans and fines still count towards circulation statistics, and its
+// open holds are cancelled.
+func (s *UserDataService) CloseAccount(userID int64, actor models.Actor) error {
+	if err := requireSelfOrPermission(s.userRepo, userID, actor.UserID, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Only super admins may close another admin's account
+	if userID != actor.UserID && models.HasPermission(user.Role, models.PermUsersManage) {
+		if _, err := requirePermission(s.userRepo, actor.UserID, models.PermAdminsManage); err != nil {
+			return err
+		}
+	}
+
+	var readied []*models.Reservation
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.userRepo.LockForUpdate(tx, userID); err != nil {
+			return err
+		}
+
+		openLoans, err := s.borrowingRepo.CountActiveByUser(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to count open loans: %w", err)
+		}
+		if openLoans > 0 {
+			return models.ErrAccountHasOpenLoans
+		}
+
+		balance, err := s.ledgerRepo.Balance(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get balance: %w", err)
+		}
+		if roundCents(balance.Balance) > 0 {
+			return models.ErrAccountHasBalance
+		}
+
+		readied, err = s.reservationService.CancelUserHolds(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel holds: %w", err)
+		}
+
+		if err := s.userRepo.Anonymize(tx, userID); err != nil {
+			return err
+		}
+
+		// The trail records that the account was closed, not what it held
+		return s.activityRepo.Create(tx, &models.ActivityLog{
+			UserID:     actorUserID(actor),
+			Action:     models.ActivityClose,
+			EntityType: models.EntityUser,
+			EntityID:   userID,
+			IPAddress:  actor.IPAddress,
+			RequestID:  actor.RequestID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range readied {
+		s.reservationService.AnnounceReady(reservation)
+	}
+
+	return nil
+}
+
+// ExportData gathers everything held about a patron: their profile, loans,
+// fines, account entries, open holds and sign-in history
+func (s *UserDataService) ExportData(userID, actorID int64) (*models.UserDataExport, error) {
+	if err := requireSelfOrPermission(s.userRepo, userID, actorID, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.UserDataExport{
+		ExportedAt:      time.Now().UTC(),
+		Profile:         user,
+		Loans:           []*models.Borrowing{},
+		FineAssessments: []*models.FineAssessment{},
+		AccountEntries:  []*models.LedgerEntry{},
+		Holds:           []*models.Reservation{},
+		AuthLogs:        []*models.AuthLog{},
+	}
+
+	for page := 1; ; page++ {
+		loans, err := s.borrowingRepo.ListByUser(userID, page, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list loans: %w", err)
+		}
+		export.Loans = append(export.Loans, loans...)
+		if len(loans) < exportPageSize {
+			break
+		}
+	}
+
+	assessments, err := s.fineRepo.ListAssessmentsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fine assessments: %w", err)
+	}
+	export.FineAssessments = append(export.FineAssessments, assessments...)
+
+	for page := 1; ; page++ {
+		entries, err := s.ledgerRepo.ListByUser(userID, page, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+		}
+		export.AccountEntries = append(export.AccountEntries, entries...)
+		if len(entries) < exportPageSize {
+			break
+		}
+	}
+
+	holds, err := s.reservationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+	export.Holds = append(export.Holds, holds...)
+
+	filters := models.AuthLogFilters{UserID: userID}
+	err = s.authLogRepo.EachMatching(filters, s.config.ExportMaxRows, func(log *models.AuthLog) error {
+		export.AuthLogs = append(export.AuthLogs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auth logs: %w", err)
+	}
+
+	return export, nil
+}
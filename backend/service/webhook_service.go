@@ -0,0 +1,442 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/internal/repository"
+	"library-management-system/pkg/logger"
+)
+
+// Webhook request headers
+const (
+	webhookEventHeader     = "X-Library-Event"
+	webhookDeliveryHeader  = "X-Library-Delivery"
+	webhookTimestampHeader = "X-Library-Timestamp"
+	webhookSignatureHeader = "X-Library-Signature"
+)
+
+// Webhook retry timing
+const (
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+)
+
+// WebhookOptions tunes webhook delivery
+type WebhookOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+
+	// MaxAttempts is how often a delivery is tried before it is given up
+	MaxAttempts int
+
+	// DisableAfter is how many failed attempts in a row disable an endpoint
+	DisableAfter int
+}
+
+// webhookDeliveryStore is the part of the webhook repository the delivery
+// worker needs, so deliveries can be tested without a database
+type webhookDeliveryStore interface {
+	ListDueDeliveries(now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	GetByID(id int64) (*models.Webhook, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	RecordSuccess(id int64) error
+	RecordFailure(id int64, disableAfter int) (bool, error)
+}
+
+// WebhookService manages webhook subscriptions and delivers library events
+// to them. It subscribes to the outbox as "webhooks" and queues one delivery
+// per matching endpoint, which its worker then sends with retries.
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	userRepo    *repository.UserRepository
+	deliveries  webhookDeliveryStore
+	client      *http.Client
+	logger      *logger.Logger
+	options     WebhookOptions
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewWebhookService creates a new WebhookService instance. The HTTP client
+// is injected so deliveries can be pointed at a test server.
+func NewWebhookService(
+	webhookRepo *repository.WebhookRepository,
+	userRepo *repository.UserRepository,
+	client *http.Client,
+	logger *logger.Logger,
+	options WebhookOptions,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+		deliveries:  webhookRepo,
+		client:      client,
+		logger:      logger,
+		options:     options,
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// ListWebhooks retrieves every webhook without its secret
+func (s *WebhookService) ListWebhooks(adminID int64) ([]*models.Webhook, error) {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhookRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook retrieves a webhook without its secret
+func (s *WebhookService) GetWebhook(id, adminID int64) (*models.Webhook, error) {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook registers a new endpoint. A signing secret is generated when
+// none is given; the returned webhook is the only place it is shown.
+func (s *WebhookService) CreateWebhook(webhook *models.Webhook, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return err
+	}
+
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		webhook.Secret = secret
+	}
+
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	webhook.Active = true
+	webhook.CreatedBy = &adminID
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWebhook changes an endpoint's address, event filter or active flag
+func (s *WebhookService) UpdateWebhook(webhook *models.Webhook, adminID int64) (*models.Webhook, error) {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return nil, err
+	}
+
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	return s.GetWebhook(webhook.ID, adminID)
+}
+
+// DeleteWebhook removes an endpoint and its delivery log
+func (s *WebhookService) DeleteWebhook(id, adminID int64) error {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.Delete(id)
+}
+
+// ListDeliveries retrieves a webhook's delivery log
+func (s *WebhookService) ListDeliveries(webhookID, adminID int64, page, pageSize int) ([]*models.WebhookDelivery, int, error) {
+	if _, err := requirePermission(s.userRepo, adminID, models.PermWebhooksManage); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := s.webhookRepo.GetByID(webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(webhookID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.webhookRepo.CountDeliveries(webhookID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// Name identifies the webhook subscriber to the outbox dispatcher
+func (s *WebhookService) Name() string {
+	return "webhooks"
+}
+
+// Handle queues an outbox event for every active webhook that wants it
+func (s *WebhookService) Handle(event *models.OutboxEvent) error {
+	webhooks, err := s.webhookRepo.ListActive()
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":          event.ID,
+		"type":        event.EventType,
+		"occurred_at": event.CreatedAt,
+		"data":        event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.EventType) {
+			continue
+		}
+
+		if err := s.webhookRepo.EnqueueDelivery(webhook.ID, event, payload); err != nil {
+			return fmt.Errorf("failed to queue delivery to webhook %d: %w", webhook.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Start sends due deliveries immediately and then on every poll interval
+// until Stop is called
+func (s *WebhookService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.options.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.DeliverPending(time.Now()); err != nil {
+				s.logger.Error("Webhook delivery failed", "error", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to finish and waits for the current batch to end
+func (s *WebhookService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// DeliverPending attempts every delivery that is due and returns how many
+// succeeded
+func (s *WebhookService) DeliverPending(now time.Time) (int, error) {
+	deliveries, err := s.deliveries.ListDueDeliveries(now, s.options.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due deliveries: %w", err)
+	}
+
+	// Webhooks are loaded once per batch; ones disabled mid-batch are skipped
+	webhooks := make(map[int64]*models.Webhook)
+	succeeded := 0
+
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = s.deliveries.GetByID(delivery.WebhookID)
+			if err != nil {
+				return succeeded, fmt.Errorf("failed to load webhook %d: %w", delivery.WebhookID, err)
+			}
+			webhooks[webhook.ID] = webhook
+		}
+
+		if !webhook.Active {
+			continue
+		}
+
+		sent, err := s.attempt(webhook, delivery)
+		if err != nil {
+			return succeeded, err
+		}
+		if sent {
+			succeeded++
+		}
+	}
+
+	return succeeded, nil
+}
+
+// attempt sends one delivery and records the outcome. It reports whether the
+// endpoint accepted it.
+func (s *WebhookService) attempt(webhook *models.Webhook, delivery *models.WebhookDelivery) (bool, error) {
+	started := time.Now()
+	status, sendErr := s.send(webhook, delivery)
+	duration := time.Since(started).Milliseconds()
+
+	delivery.Attempts++
+	delivery.DurationMs = &duration
+	delivery.ResponseStatus = nil
+	if status > 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	if sendErr == nil {
+		now := time.Now()
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+
+		if err := s.deliveries.UpdateDelivery(delivery); err != nil {
+			return true, fmt.Errorf("failed to record delivery: %w", err)
+		}
+		if err := s.deliveries.RecordSuccess(webhook.ID); err != nil {
+			return true, fmt.Errorf("failed to record webhook success: %w", err)
+		}
+		webhook.ConsecutiveFailures = 0
+
+		return true, nil
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= s.options.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	} else {
+		next := time.Now().Add(webhookBackoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := s.deliveries.UpdateDelivery(delivery); err != nil {
+		return false, fmt.Errorf("failed to record delivery: %w", err)
+	}
+
+	disabled, err := s.deliveries.RecordFailure(webhook.ID, s.options.DisableAfter)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	if disabled {
+		webhook.Active = false
+		s.logger.Error("Webhook disabled after repeated failures", "webhook_id", webhook.ID, "url", webhook.URL)
+	}
+
+	return false, nil
+}
+
+// send posts a signed delivery to its webhook and returns the response status.
+// Any status outside 2xx counts as a failure.
+func (s *WebhookService) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "library-webhooks/1.0")
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "timestamp.payload".
+// Receivers recompute it with their secret to verify a delivery.
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns how long to wait after the given number of failed
+// attempts: 30s, 1m, 2m, 4m and so on, up to webhookRetryMax
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+
+	if delay > webhookRetryMax {
+		return webhookRetryMax
+	}
+	return delay
+}
+
+// generateWebhookSecret creates a random signing secret
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
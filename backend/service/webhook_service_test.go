@@ -0,0 +1,380 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"library-management-system/internal/models"
+	"library-management-system/pkg/logger"
+)
+
+// fakeWebhookStore keeps webhooks and deliveries in memory and applies the
+// same failure streak rule as the repository
+type fakeWebhookStore struct {
+	mu         sync.Mutex
+	webhooks   map[int64]*models.Webhook
+	deliveries []*models.WebhookDelivery
+	successes  int
+}
+
+func newFakeWebhookStore(webhooks ...*models.Webhook) *fakeWebhookStore {
+	store := &fakeWebhookStore{webhooks: make(map[int64]*models.Webhook)}
+	for _, webhook := range webhooks {
+		store.webhooks[webhook.ID] = webhook
+	}
+	return store
+}
+
+func (f *fakeWebhookStore) ListDueDeliveries(now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var due []*models.WebhookDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.Status != models.WebhookDeliveryPending || !f.webhooks[delivery.WebhookID].Active {
+			continue
+		}
+		if delivery.NextAttemptAt != nil && delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		copied := *delivery
+		due = append(due, &copied)
+		if len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeWebhookStore) GetByID(id int64) (*models.Webhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	webhook, ok := f.webhooks[id]
+	if !ok {
+		return nil, models.ErrWebhookNotFound
+	}
+	copied := *webhook
+	return &copied, nil
+}
+
+func (f *fakeWebhookStore) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, stored := range f.deliveries {
+		if stored.ID == delivery.ID {
+			copied := *delivery
+			f.deliveries[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (f *fakeWebhookStore) RecordSuccess(id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.webhooks[id].ConsecutiveFailures = 0
+	f.successes++
+	return nil
+}
+
+func (f *fakeWebhookStore) RecordFailure(id int64, disableAfter int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	webhook := f.webhooks[id]
+	webhook.ConsecutiveFailures++
+	disabled := webhook.Active && webhook.ConsecutiveFailures >= disableAfter
+	if disabled {
+		webhook.Active = false
+	}
+	return disabled, nil
+}
+
+func (f *fakeWebhookStore) delivery(id int64) models.WebhookDelivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, delivery := range f.deliveries {
+		if delivery.ID == id {
+			return *delivery
+		}
+	}
+	return models.WebhookDelivery{}
+}
+
+func newTestWebhookService(store *fakeWebhookStore, client *http.Client, options WebhookOptions) *WebhookService {
+	service := NewWebhookService(nil, nil, client, logger.NewLogger(), options)
+	service.deliveries = store
+	return service
+}
+
+func pendingDelivery(id, webhookID int64) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        id,
+		WebhookID: webhookID,
+		EventID:   id,
+		EventType: models.EventLoanCreated,
+		Payload:   []byte(`{"id":` + strconv.FormatInt(id, 10) + `,"type":"loan.created"}`),
+		Status:    models.WebhookDeliveryPending,
+	}
+}
+
+func TestDeliverPendingSignsRequests(t *testing.T) {
+	const secret = "test-secret"
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: 1, URL: server.URL, Secret: secret, Active: true}
+	store := newFakeWebhookStore(webhook)
+	store.deliveries = []*models.WebhookDelivery{pendingDelivery(7, webhook.ID)}
+
+	service := newTestWebhookService(store, server.Client(), WebhookOptions{BatchSize: 10, MaxAttempts: 3, DisableAfter: 5})
+
+	succeeded, err := service.DeliverPending(time.Now())
+	if err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+	if succeeded != 1 {
+		t.Fatalf("DeliverPending() = %d, want 1", succeeded)
+	}
+
+	req := <-requests
+	if got := req.header.Get(webhookEventHeader); got != models.EventLoanCreated {
+		t.Errorf("%s = %q, want %q", webhookEventHeader, got, models.EventLoanCreated)
+	}
+	if got := req.header.Get(webhookDeliveryHeader); got != "7" {
+		t.Errorf("%s = %q, want %q", webhookDeliveryHeader, got, "7")
+	}
+
+	timestamp := req.header.Get(webhookTimestampHeader)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("%s = %q is not a unix time", webhookTimestampHeader, timestamp)
+	}
+
+	want := "sha256=" + SignWebhookPayload(secret, timestamp, req.body)
+	if got := req.header.Get(webhookSignatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", webhookSignatureHeader, got, want)
+	}
+	if string(req.body) != string(store.deliveries[0].Payload) {
+		t.Errorf("body = %s, want the stored payload", req.body)
+	}
+
+	delivery := store.delivery(7)
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		t.Errorf("status = %q, want %q", delivery.Status, models.WebhookDeliverySucceeded)
+	}
+	if delivery.Attempts != 1 || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want one attempt, delivered and nothing scheduled", delivery)
+	}
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("response status = %v, want %d", delivery.ResponseStatus, http.StatusNoContent)
+	}
+	if store.successes != 1 {
+		t.Errorf("successes recorded = %d, want 1", store.successes)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000.{}"))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhookPayload("secret", "1700000000", []byte("{}")); got != want {
+		t.Errorf("SignWebhookPayload() = %q, want %q", got, want)
+	}
+	if got := SignWebhookPayload("other", "1700000000", []byte("{}")); got == want {
+		t.Error("SignWebhookPayload() ignores the secret")
+	}
+	if got := SignWebhookPayload("secret", "1700000001", []byte("{}")); got == want {
+		t.Error("SignWebhookPayload() ignores the timestamp")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, webhookRetryMax},
+		{50, webhookRetryMax},
+	}
+
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverPendingRetriesUntilMaxAttempts(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: 1, URL: server.URL, Secret: "s", Active: true}
+	store := newFakeWebhookStore(webhook)
+	store.deliveries = []*models.WebhookDelivery{pendingDelivery(1, webhook.ID)}
+
+	service := newTestWebhookService(store, server.Client(), WebhookOptions{BatchSize: 10, MaxAttempts: 3, DisableAfter: 100})
+
+	now := time.Now()
+	for attempt := 1; attempt <= 3; attempt++ {
+		before := time.Now()
+		if _, err := service.DeliverPending(now); err != nil {
+			t.Fatalf("attempt %d: DeliverPending() error = %v", attempt, err)
+		}
+
+		delivery := store.delivery(1)
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: attempts = %d", attempt, delivery.Attempts)
+		}
+		if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusInternalServerError {
+			t.Errorf("attempt %d: response status = %v, want 500", attempt, delivery.ResponseStatus)
+		}
+		if delivery.LastError == "" {
+			t.Errorf("attempt %d: last error not recorded", attempt)
+		}
+
+		if attempt < 3 {
+			if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt == nil {
+				t.Fatalf("attempt %d: delivery = %+v, want a retry scheduled", attempt, delivery)
+			}
+			wait := delivery.NextAttemptAt.Sub(before)
+			if backoff := webhookBackoff(attempt); wait < backoff || wait > backoff+time.Second {
+				t.Errorf("attempt %d: retry in %v, want %v", attempt, wait, backoff)
+			}
+
+			// Nothing is sent again before the retry is due
+			if _, err := service.DeliverPending(now); err != nil {
+				t.Fatalf("attempt %d: DeliverPending() error = %v", attempt, err)
+			}
+			if got := atomic.LoadInt32(&hits); got != int32(attempt) {
+				t.Fatalf("attempt %d: endpoint hit %d times before the retry was due", attempt, got)
+			}
+
+			now = *delivery.NextAttemptAt
+		}
+	}
+
+	delivery := store.delivery(1)
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want failed with no retry", delivery)
+	}
+
+	if _, err := service.DeliverPending(now.Add(webhookRetryMax)); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("endpoint hit %d times, want 3", got)
+	}
+}
+
+func TestDeliverPendingDisablesFailingWebhook(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: 1, URL: server.URL, Secret: "s", Active: true}
+	store := newFakeWebhookStore(webhook)
+	store.deliveries = []*models.WebhookDelivery{
+		pendingDelivery(1, webhook.ID),
+		pendingDelivery(2, webhook.ID),
+		pendingDelivery(3, webhook.ID),
+	}
+
+	service := newTestWebhookService(store, server.Client(), WebhookOptions{BatchSize: 10, MaxAttempts: 8, DisableAfter: 2})
+
+	if _, err := service.DeliverPending(time.Now()); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+
+	// The third delivery in the batch is skipped once the webhook is disabled
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("endpoint hit %d times, want 2", got)
+	}
+	if store.webhooks[1].Active {
+		t.Error("webhook still active after reaching the failure limit")
+	}
+	if got := store.delivery(3).Attempts; got != 0 {
+		t.Errorf("third delivery attempts = %d, want 0", got)
+	}
+
+	// Disabled webhooks get no further deliveries
+	if _, err := service.DeliverPending(time.Now().Add(webhookRetryMax)); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("endpoint hit %d times after disabling, want 2", got)
+	}
+}
+
+func TestDeliverPendingResetsFailureStreakOnSuccess(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: 1, URL: server.URL, Secret: "s", Active: true}
+	store := newFakeWebhookStore(webhook)
+	store.deliveries = []*models.WebhookDelivery{pendingDelivery(1, webhook.ID)}
+
+	service := newTestWebhookService(store, server.Client(), WebhookOptions{BatchSize: 10, MaxAttempts: 8, DisableAfter: 2})
+
+	if _, err := service.DeliverPending(time.Now()); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+	if got := store.webhooks[1].ConsecutiveFailures; got != 1 {
+		t.Fatalf("consecutive failures = %d, want 1", got)
+	}
+
+	fail.Store(false)
+	if _, err := service.DeliverPending(time.Now().Add(webhookRetryMax)); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+	if got := store.webhooks[1].ConsecutiveFailures; got != 0 {
+		t.Errorf("consecutive failures = %d, want 0 after a success", got)
+	}
+	if !store.webhooks[1].Active {
+		t.Error("webhook disabled despite recovering")
+	}
+}